@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE mcp_audit_entries (
+    id         BIGINT PRIMARY KEY AUTO_INCREMENT,
+    time       DATETIME(3) NOT NULL,
+    session_id VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL,
+    transport  VARCHAR(64) NOT NULL,
+    client     VARCHAR(255) NOT NULL,
+    principal  VARCHAR(255) NOT NULL,
+    tool       VARCHAR(128) NOT NULL,
+    arguments  TEXT NOT NULL,
+    outcome    VARCHAR(32) NOT NULL,
+    error      TEXT NOT NULL,
+    latency_ms BIGINT NOT NULL,
+    INDEX idx_mcp_audit_entries_time (time),
+    INDEX idx_mcp_audit_entries_tool (tool),
+    INDEX idx_mcp_audit_entries_session_id (session_id),
+    INDEX idx_mcp_audit_entries_principal (principal)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS mcp_audit_entries;
@@ -28,6 +28,7 @@ var Bootstrapper = fxcore.NewBootstrapper().WithOptions(
 	fxhttpclient.FxHttpClientModule,
 	fxsql.FxSQLModule,
 	mcp.MCPServerModule,
+	mcp.MCPAuditModule,
 	// dependencies registration
 	Register(),
 )
@@ -3,6 +3,7 @@ package prompt
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ankorstore/yokai/config"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -51,3 +52,19 @@ func (p *GreetPrompt) Handle() server.PromptHandlerFunc {
 		), nil
 	}
 }
+
+func (p *GreetPrompt) Complete(ctx context.Context, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	values := []string{}
+
+	if argument.Name == "name" {
+		for _, owner := range p.config.GetStringSlice("config.books.owners") {
+			if strings.HasPrefix(strings.ToLower(owner), strings.ToLower(argument.Value)) {
+				values = append(values, owner)
+			}
+		}
+	}
+
+	return &mcp.Completion{
+		Values: values,
+	}, nil
+}
@@ -2,81 +2,54 @@ package tool
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 
+	"github.com/ankorstore/yokai/log"
 	"github.com/ekkinox/yokai-mcp/internal/domain"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
+type CreateBookToolInput struct {
+	Title    string `json:"title" required:"true" description:"Title of the book."`
+	Genre    string `json:"genre" required:"true" enum:"science-fiction,horror,romance,fantasy" description:"Genre of the book."`
+	Synopsis string `json:"synopsis" required:"true" description:"Synopsis of the book."`
+}
+
 type CreateBookTool struct {
+	*server.MCPServerTypedTool[CreateBookToolInput, domain.Book]
 	service *domain.BookService
 }
 
-func NewCreateBookTool(service *domain.BookService) *CreateBookTool {
-	return &CreateBookTool{
+func NewCreateBookTool(service *domain.BookService) (*CreateBookTool, error) {
+	tool := &CreateBookTool{
 		service: service,
 	}
-}
-
-func (t *CreateBookTool) Name() string {
-	return "create-book"
-}
 
-func (t *CreateBookTool) Options() []mcp.ToolOption {
-	return []mcp.ToolOption{
+	typedTool, err := server.NewMCPServerTypedTool(
+		"create-book",
+		tool.handle,
 		mcp.WithDescription("To create a new book."),
-		mcp.WithString(
-			"title",
-			mcp.Required(),
-			mcp.Description("Title of the book."),
-		),
-		mcp.WithString(
-			"genre",
-			mcp.Required(),
-			mcp.Description("Genre of the book."),
-			mcp.Enum("science-fiction", "horror", "romance", "fantasy"),
-		),
-		mcp.WithString(
-			"synopsis",
-			mcp.Required(),
-			mcp.Description("Synopsis of the book."),
-		),
+		mcp.WithTitleAnnotation("Create book"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+	)
+	if err != nil {
+		return nil, err
 	}
-}
-
-func (t *CreateBookTool) Handle() server.ToolHandlerFunc {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		titleParams, ok := request.Params.Arguments["title"].(string)
-		if !ok {
-			return nil, errors.New("title must be a string")
-		}
-
-		genreParam, ok := request.Params.Arguments["genre"].(string)
-		if !ok {
-			return nil, errors.New("genre must be a string")
-		}
 
-		synopsisParam, ok := request.Params.Arguments["synopsis"].(string)
-		if !ok {
-			return nil, errors.New("synopsis must be a string")
-		}
+	tool.MCPServerTypedTool = typedTool
 
-		book, err := t.service.CreateBook(ctx, domain.CreateBookParams{
-			Title:    titleParams,
-			Genre:    genreParam,
-			Synopsis: synopsisParam,
-		})
-		if err != nil {
-			return nil, err
-		}
+	return tool, nil
+}
 
-		jsonBook, err := json.Marshal(book)
-		if err != nil {
-			return nil, err
-		}
+func (t *CreateBookTool) handle(ctx context.Context, input CreateBookToolInput) (domain.Book, error) {
+	log.CtxLogger(ctx).Info().Msg("creating book")
 
-		return mcp.NewToolResultText(string(jsonBook)), nil
-	}
+	return t.service.CreateBook(ctx, domain.CreateBookParams{
+		Title:    input.Title,
+		Genre:    input.Genre,
+		Synopsis: input.Synopsis,
+	})
 }
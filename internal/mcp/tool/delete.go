@@ -6,68 +6,62 @@ import (
 	"strconv"
 
 	"github.com/ekkinox/yokai-mcp/internal/domain"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
+type DeleteBookToolInput struct {
+	ID    string `json:"id" description:"Optional ID of the book to delete. Empty value means no book selection by id."`
+	Genre string `json:"genre" enum:",science-fiction,horror,romance,fantasy" description:"Optional genre of the book. Empty value means no books selection by genre."`
+}
+
 type DeleteBookTool struct {
+	*server.MCPServerTypedTool[DeleteBookToolInput, string]
 	service *domain.BookService
 }
 
-func NewDeleteBookTool(service *domain.BookService) *DeleteBookTool {
-	return &DeleteBookTool{
+func NewDeleteBookTool(service *domain.BookService) (*DeleteBookTool, error) {
+	tool := &DeleteBookTool{
 		service: service,
 	}
-}
 
-func (t *DeleteBookTool) Name() string {
-	return "delete-book"
-}
-
-func (t *DeleteBookTool) Options() []mcp.ToolOption {
-	return []mcp.ToolOption{
+	typedTool, err := server.NewMCPServerTypedTool(
+		"delete-book",
+		tool.handle,
 		mcp.WithDescription("To delete one or several existing books."),
-		mcp.WithString(
-			"id",
-			mcp.DefaultString(""),
-			mcp.Description("Optional ID of the book to delete. Empty value means no book selection by id."),
-		),
-		mcp.WithString(
-			"genre",
-			mcp.DefaultString(""),
-			mcp.Description("Optional genre of the book. Empty value means bo books selection by genre."),
-			mcp.Enum("", "science-fiction", "horror", "romance", "fantasy"),
-		),
+		mcp.WithTitleAnnotation("Delete books"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (t *DeleteBookTool) Handle() server.ToolHandlerFunc {
-	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		id := 0
-		idParam, ok := request.Params.Arguments["id"]
-		if ok {
-			var err error
+	tool.MCPServerTypedTool = typedTool
 
-			id, err = strconv.Atoi(idParam.(string))
-			if err != nil {
-				return nil, err
-			}
-		}
+	return tool, nil
+}
 
-		genre := ""
-		genreParam, ok := request.Params.Arguments["genre"]
-		if ok {
-			genre = genreParam.(string)
-		}
+func (t *DeleteBookTool) handle(ctx context.Context, input DeleteBookToolInput) (string, error) {
+	id := 0
+	if input.ID != "" {
+		var err error
 
-		rowsAffected, err := t.service.DeleteBook(ctx, domain.DeleteBookParams{
-			ID:    id,
-			Genre: genre,
-		})
+		id, err = strconv.Atoi(input.ID)
 		if err != nil {
-			return nil, err
+			return "", fmt.Errorf("invalid book id %q: %w", input.ID, err)
 		}
+	}
 
-		return mcp.NewToolResultText(fmt.Sprintf("%d books were deleted", rowsAffected)), nil
+	rowsAffected, err := t.service.DeleteBook(ctx, domain.DeleteBookParams{
+		ID:    id,
+		Genre: input.Genre,
+	})
+	if err != nil {
+		return "", err
 	}
+
+	return fmt.Sprintf("%d books were deleted", rowsAffected), nil
 }
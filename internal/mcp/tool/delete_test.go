@@ -0,0 +1,35 @@
+package tool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/internal/mcp/tool"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteBookToolInputSchema(t *testing.T) {
+	t.Parallel()
+
+	deleteTool, err := tool.NewDeleteBookTool(nil)
+	require.NoError(t, err)
+
+	mcpTool := mcp.NewTool(deleteTool.Name(), deleteTool.Options()...)
+	assert.Equal(t, "delete-book", mcpTool.Name)
+	assert.Equal(t, "string", mcpTool.InputSchema.Properties["id"].(map[string]any)["type"])
+}
+
+func TestDeleteBookToolWithInvalidID(t *testing.T) {
+	t.Parallel()
+
+	deleteTool, err := tool.NewDeleteBookTool(nil)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"id": "abc"}
+
+	_, err = deleteTool.Handle()(context.Background(), request)
+	assert.ErrorContains(t, err, `invalid book id "abc"`)
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/ankorstore/yokai/config"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+const AuditModuleName = "mcpaudit"
+
+// MCPAuditModule is the optional audit trail module of MCP tool invocations, persisted through the fxsql *sql.DB.
+//
+// The audit HTTP handler is registered only if modules.mcp.server.audit.enabled is true, and is protected by the
+// auth.MCPAuthenticator. The audit resource is registered only if modules.mcp.server.audit.resource.enabled is also
+// true, and exposes only the entries of the authenticated caller.
+var MCPAuditModule = fx.Module(
+	AuditModuleName,
+	fx.Provide(
+		ProvideMCPAuditRepository,
+		audit.NewMCPAuditHook,
+		audit.NewMCPAuditPruner,
+		fx.Annotate(
+			ProvideMCPAuditServerHook,
+			fx.ResultTags(`group:"mcp-server-hooks"`),
+		),
+		fx.Annotate(
+			ProvideMCPAuditServerResources,
+			fx.ResultTags(`group:"mcp-server-resources,flatten"`),
+		),
+	),
+	fx.Invoke(
+		RunMCPAuditHook,
+		RunMCPAuditPruner,
+		RegisterMCPAuditHandler,
+	),
+)
+
+type ProvideMCPAuditRepositoryParams struct {
+	fx.In
+	Config *config.Config
+	DB     *sql.DB `optional:"true"`
+}
+
+func ProvideMCPAuditRepository(p ProvideMCPAuditRepositoryParams) (*audit.MCPAuditRepository, error) {
+	if p.DB == nil && p.Config.GetBool("modules.mcp.server.audit.enabled") {
+		return nil, fmt.Errorf("cannot enable MCP audit: no SQL database available")
+	}
+
+	return audit.NewMCPAuditRepository(p.DB), nil
+}
+
+func ProvideMCPAuditServerHook(hook *audit.MCPAuditHook) yokaimcpserver.MCPServerHook {
+	return hook
+}
+
+type ProvideMCPAuditServerResourcesParams struct {
+	fx.In
+	Config     *config.Config
+	Repository *audit.MCPAuditRepository
+}
+
+func ProvideMCPAuditServerResources(p ProvideMCPAuditServerResourcesParams) []yokaimcpserver.MCPServerResource {
+	if !p.Config.GetBool("modules.mcp.server.audit.enabled") ||
+		!p.Config.GetBool("modules.mcp.server.audit.resource.enabled") {
+		return nil
+	}
+
+	return []yokaimcpserver.MCPServerResource{
+		audit.NewMCPAuditResource(p.Config, p.Repository),
+	}
+}
+
+type RunMCPAuditHookParams struct {
+	fx.In
+	LifeCycle fx.Lifecycle
+	Context   context.Context
+	Hook      *audit.MCPAuditHook
+}
+
+func RunMCPAuditHook(p RunMCPAuditHookParams) {
+	if !p.Hook.Enabled() {
+		return
+	}
+
+	p.LifeCycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			p.Hook.Start(p.Context)
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			p.Hook.Stop()
+
+			return nil
+		},
+	})
+}
+
+type RunMCPAuditPrunerParams struct {
+	fx.In
+	LifeCycle fx.Lifecycle
+	Context   context.Context
+	Pruner    *audit.MCPAuditPruner
+}
+
+func RunMCPAuditPruner(p RunMCPAuditPrunerParams) {
+	if !p.Pruner.Enabled() {
+		return
+	}
+
+	p.LifeCycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			p.Pruner.Start(p.Context)
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			p.Pruner.Stop()
+
+			return nil
+		},
+	})
+}
+
+type RegisterMCPAuditHandlerParams struct {
+	fx.In
+	Config        *config.Config
+	Repository    *audit.MCPAuditRepository
+	Authenticator auth.MCPAuthenticator
+	HttpServer    *echo.Echo `optional:"true"`
+}
+
+func RegisterMCPAuditHandler(p RegisterMCPAuditHandlerParams) {
+	if p.HttpServer == nil || !p.Config.GetBool("modules.mcp.server.audit.enabled") {
+		return
+	}
+
+	p.HttpServer.GET(
+		audit.HandlerPath,
+		audit.NewMCPAuditHandler(p.Repository).Handle(),
+		echo.WrapMiddleware(func(next http.Handler) http.Handler {
+			return auth.Middleware(p.Authenticator, next)
+		}),
+	)
+}
@@ -0,0 +1,161 @@
+package mcp_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type auditTestResult struct {
+	fx.In
+	Resources []yokaimcpserver.MCPServerResource `group:"mcp-server-resources"`
+	Hooks     []yokaimcpserver.MCPServerHook     `group:"mcp-server-hooks"`
+}
+
+// runAuditTestApp runs the MCPAuditModule with a given config and optional options.
+func runAuditTestApp(tb testing.TB, content string, options ...fx.Option) (*echo.Echo, auditTestResult, error) {
+	tb.Helper()
+
+	cfg := mcptest.NewConfig(tb, content)
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(cfg)
+	require.NoError(tb, err)
+
+	httpServer := echo.New()
+
+	var result auditTestResult
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(cfg, redactor, httpServer),
+		fx.Provide(
+			func() context.Context { return context.Background() },
+			fx.Annotate(
+				func() *auth.StaticBearerTokenAuthenticator {
+					return auth.NewStaticBearerTokenAuthenticator(map[string]string{"admin": "secret"})
+				},
+				fx.As(new(auth.MCPAuthenticator)),
+			),
+		),
+		mcp.MCPAuditModule,
+		fx.Options(options...),
+		fx.Populate(&result),
+	)
+
+	return httpServer, result, app.Err()
+}
+
+func TestMCPAuditModuleDisabled(t *testing.T) {
+	t.Parallel()
+
+	httpServer, result, err := runAuditTestApp(t, "app:\n  name: test\n")
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Resources)
+	assert.Len(t, result.Hooks, 1)
+
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, audit.HandlerPath, nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMCPAuditModuleEnabledWithoutDatabase(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := runAuditTestApp(t, `
+modules:
+  mcp:
+    server:
+      audit:
+        enabled: true
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot enable MCP audit: no SQL database available")
+}
+
+func TestMCPAuditModuleEnabled(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	_, err = db.Exec(`
+		CREATE TABLE mcp_audit_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, time DATETIME NOT NULL, session_id TEXT NOT NULL,
+			request_id TEXT NOT NULL, transport TEXT NOT NULL, client TEXT NOT NULL, principal TEXT NOT NULL,
+			tool TEXT NOT NULL, arguments TEXT NOT NULL, outcome TEXT NOT NULL, error TEXT NOT NULL,
+			latency_ms BIGINT NOT NULL
+		)
+	`)
+	require.NoError(t, err)
+
+	httpServer, result, err := runAuditTestApp(t, `
+modules:
+  mcp:
+    server:
+      audit:
+        enabled: true
+`, fx.Supply(db))
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Resources)
+
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, audit.HandlerPath, nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, audit.HandlerPath, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+}
+
+func TestMCPAuditModuleEnabledWithResource(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	_, result, err := runAuditTestApp(t, `
+modules:
+  mcp:
+    server:
+      audit:
+        enabled: true
+        resource:
+          enabled: true
+`, fx.Supply(db))
+	require.NoError(t, err)
+
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, audit.ResourceName, result.Resources[0].Name())
+}
@@ -5,26 +5,34 @@ import (
 	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/stdio"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/streamablehttp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/websocket"
 )
 
 type MCPServerModuleInfo struct {
-	config      *config.Config
-	registry    *yokaimcpserver.MCPServerRegistry
-	sseServer   *sse.MCPSSEServer
-	stdioServer *stdio.MCPStdioServer
+	config               *config.Config
+	registry             *yokaimcpserver.MCPServerRegistry
+	sseServer            *sse.MCPSSEServer
+	streamableHTTPServer *streamablehttp.MCPStreamableHTTPServer
+	webSocketServer      *websocket.MCPWebSocketServer
+	stdioServer          *stdio.MCPStdioServer
 }
 
 func NewMCPServerModuleInfo(
 	config *config.Config,
 	registry *yokaimcpserver.MCPServerRegistry,
 	sseServer *sse.MCPSSEServer,
+	streamableHTTPServer *streamablehttp.MCPStreamableHTTPServer,
+	webSocketServer *websocket.MCPWebSocketServer,
 	stdioServer *stdio.MCPStdioServer,
 ) *MCPServerModuleInfo {
 	return &MCPServerModuleInfo{
-		config:      config,
-		registry:    registry,
-		sseServer:   sseServer,
-		stdioServer: stdioServer,
+		config:               config,
+		registry:             registry,
+		sseServer:            sseServer,
+		streamableHTTPServer: streamableHTTPServer,
+		webSocketServer:      webSocketServer,
+		stdioServer:          stdioServer,
 	}
 }
 
@@ -36,14 +44,19 @@ func (i *MCPServerModuleInfo) Name() string {
 // Data return the data of the module info.
 func (i *MCPServerModuleInfo) Data() map[string]interface{} {
 	sseServerInfo := i.sseServer.Info()
+	streamableHTTPServerInfo := i.streamableHTTPServer.Info()
+	webSocketServerInfo := i.webSocketServer.Info()
 	stdioServerInfo := i.stdioServer.Info()
 	mcpRegistryInfo := i.registry.Info()
 
 	return map[string]interface{}{
 		"transports": map[string]interface{}{
-			"sse":   sseServerInfo,
-			"stdio": stdioServerInfo,
+			"sse":             sseServerInfo,
+			"streamable_http": streamableHTTPServerInfo,
+			"websocket":       webSocketServerInfo,
+			"stdio":           stdioServerInfo,
 		},
+		"readOnly": mcpRegistryInfo.ReadOnly,
 		"capabilities": map[string]interface{}{
 			"tools":     mcpRegistryInfo.Capabilities.Tools,
 			"prompts":   mcpRegistryInfo.Capabilities.Prompts,
@@ -55,5 +68,11 @@ func (i *MCPServerModuleInfo) Data() map[string]interface{} {
 			"resources":         mcpRegistryInfo.Registrations.Resources,
 			"resourceTemplates": mcpRegistryInfo.Registrations.ResourceTemplates,
 		},
+		"filtered": map[string]interface{}{
+			"tools":             mcpRegistryInfo.Filtered.Tools,
+			"prompts":           mcpRegistryInfo.Filtered.Prompts,
+			"resources":         mcpRegistryInfo.Filtered.Resources,
+			"resourceTemplates": mcpRegistryInfo.Filtered.ResourceTemplates,
+		},
 	}
 }
@@ -0,0 +1,25 @@
+package mcptest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/stretchr/testify/require"
+)
+
+// NewConfig returns a config loaded from a given YAML content, written as config.yaml in a temporary directory.
+func NewConfig(tb testing.TB, content string) *config.Config {
+	tb.Helper()
+
+	dir := tb.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600)
+	require.NoError(tb, err)
+
+	cfg, err := config.NewDefaultConfigFactory().Create(config.WithFilePaths(dir))
+	require.NoError(tb, err)
+
+	return cfg
+}
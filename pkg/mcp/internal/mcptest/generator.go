@@ -0,0 +1,28 @@
+package mcptest
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ankorstore/yokai/generate/uuid"
+)
+
+var _ uuid.UuidGenerator = (*SequenceUuidGenerator)(nil)
+
+// SequenceUuidGenerator is a uuid.UuidGenerator returning predictable values: a given prefix followed by a sequence
+// number, starting at 1.
+type SequenceUuidGenerator struct {
+	prefix string
+	count  atomic.Int64
+}
+
+// NewSequenceUuidGenerator returns a new SequenceUuidGenerator, for a given prefix.
+func NewSequenceUuidGenerator(prefix string) *SequenceUuidGenerator {
+	return &SequenceUuidGenerator{
+		prefix: prefix,
+	}
+}
+
+func (g *SequenceUuidGenerator) Generate() string {
+	return fmt.Sprintf("%s-%d", g.prefix, g.count.Add(1))
+}
@@ -2,15 +2,22 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ankorstore/yokai/config"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/log"
 	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/stdio"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/streamablehttp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/websocket"
+	"github.com/labstack/echo/v4"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
@@ -22,8 +29,13 @@ var MCPServerModule = fx.Module(
 	fx.Provide(
 		// module fixed dependencies
 		ProvideMCPServerRegistry,
+		ProvideMCPServerRedactor,
+		ProvideMCPServerMetricTargetResolver,
+		ProvideMCPServerMetrics,
 		ProvideMCPServer,
 		ProvideMCPSSEServer,
+		ProvideMCPStreamableHTTPServer,
+		ProvideMCPWebSocketServer,
 		ProvideMCPStdioServer,
 		// module overridable dependencies
 		fx.Annotate(
@@ -34,6 +46,10 @@ var MCPServerModule = fx.Module(
 			ProvideDefaultMCPServerFactory,
 			fx.As(new(yokaimcpserver.MCPServerFactory)),
 		),
+		fx.Annotate(
+			ProvideDefaultMCPAuthenticator,
+			fx.As(new(auth.MCPAuthenticator)),
+		),
 		fx.Annotate(
 			ProvideDefaultMCPSSEServerContextHandler,
 			fx.As(new(sse.MCPSSEServerContextHandler)),
@@ -42,6 +58,22 @@ var MCPServerModule = fx.Module(
 			ProvideDefaultMCPSSEServerFactory,
 			fx.As(new(sse.MCPSSEServerFactory)),
 		),
+		fx.Annotate(
+			ProvideDefaultMCPStreamableHTTPServerContextHandler,
+			fx.As(new(streamablehttp.MCPStreamableHTTPServerContextHandler)),
+		),
+		fx.Annotate(
+			ProvideDefaultMCPStreamableHTTPServerFactory,
+			fx.As(new(streamablehttp.MCPStreamableHTTPServerFactory)),
+		),
+		fx.Annotate(
+			ProvideDefaultMCPWebSocketServerContextHandler,
+			fx.As(new(websocket.MCPWebSocketServerContextHandler)),
+		),
+		fx.Annotate(
+			ProvideDefaultMCPWebSocketServerFactory,
+			fx.As(new(websocket.MCPWebSocketServerFactory)),
+		),
 		fx.Annotate(
 			ProvideDefaultMCPStdioServerContextHandler,
 			fx.As(new(stdio.MCPStdioServerContextHandler)),
@@ -61,12 +93,56 @@ var MCPServerModule = fx.Module(
 
 type ProvideDefaultMCPServerHooksProviderParams struct {
 	fx.In
-	Registry *prometheus.Registry
 	Config   *config.Config
+	Redactor *yokaimcpserver.MCPServerRedactor
+	Targets  *yokaimcpserver.MCPServerMetricTargetResolver
+	Metrics  yokaimcpserver.MCPServerMetrics
 }
 
 func ProvideDefaultMCPServerHooksProvider(p ProvideDefaultMCPServerHooksProviderParams) *yokaimcpserver.DefaultMCPServerHooksProvider {
-	return yokaimcpserver.NewDefaultMCPServerHooksProvider(p.Registry, p.Config)
+	return yokaimcpserver.NewDefaultMCPServerHooksProvider(p.Config, p.Redactor, p.Targets, p.Metrics)
+}
+
+type ProvideMCPServerMetricsParams struct {
+	fx.In
+	Config        *config.Config
+	Registry      *prometheus.Registry
+	MeterProvider metric.MeterProvider `optional:"true"`
+}
+
+func ProvideMCPServerMetrics(p ProvideMCPServerMetricsParams) (yokaimcpserver.MCPServerMetrics, error) {
+	switch backend := p.Config.GetString("modules.mcp.server.metrics.backend"); backend {
+	case "", yokaimcpserver.MetricsBackendPrometheus:
+		return yokaimcpserver.NewPrometheusMCPServerMetrics(p.Registry, p.Config), nil
+	case yokaimcpserver.MetricsBackendOTel:
+		meterProvider := p.MeterProvider
+		if meterProvider == nil {
+			meterProvider = otel.GetMeterProvider()
+		}
+
+		return yokaimcpserver.NewOTelMCPServerMetrics(meterProvider, p.Config)
+	default:
+		return nil, fmt.Errorf("invalid MCP server metrics backend %q", backend)
+	}
+}
+
+type ProvideMCPServerMetricTargetResolverParams struct {
+	fx.In
+	Config   *config.Config
+	Registry *yokaimcpserver.MCPServerRegistry
+}
+
+func ProvideMCPServerMetricTargetResolver(p ProvideMCPServerMetricTargetResolverParams) *yokaimcpserver.MCPServerMetricTargetResolver {
+	return yokaimcpserver.NewMCPServerMetricTargetResolver(p.Config, p.Registry)
+}
+
+type ProvideMCPServerRedactorParams struct {
+	fx.In
+	Config *config.Config
+}
+
+func ProvideMCPServerRedactor(p ProvideMCPServerRedactorParams) (*yokaimcpserver.MCPServerRedactor, error) {
+	return yokaimcpserver.NewMCPServerRedactor(p.Config)
 }
 
 type ProvideDefaultMCPServerFactoryParams struct {
@@ -80,20 +156,26 @@ func ProvideDefaultMCPServerFactory(p ProvideDefaultMCPServerFactoryParams) *yok
 
 type ProvideMCPServerRegistryParams struct {
 	fx.In
-	Config            *config.Config
-	Tools             []yokaimcpserver.MCPServerTool             `group:"mcp-server-tools"`
-	Prompts           []yokaimcpserver.MCPServerPrompt           `group:"mcp-server-prompts"`
-	Resources         []yokaimcpserver.MCPServerResource         `group:"mcp-server-resources"`
-	ResourceTemplates []yokaimcpserver.MCPServerResourceTemplate `group:"mcp-server-resource-templates"`
+	Config              *config.Config
+	Tools               []yokaimcpserver.MCPServerTool               `group:"mcp-server-tools"`
+	Prompts             []yokaimcpserver.MCPServerPrompt             `group:"mcp-server-prompts"`
+	Resources           []yokaimcpserver.MCPServerResource           `group:"mcp-server-resources"`
+	ResourceTemplates   []yokaimcpserver.MCPServerResourceTemplate   `group:"mcp-server-resource-templates"`
+	ToolMiddlewares     []yokaimcpserver.MCPServerToolMiddleware     `group:"mcp-server-tool-middlewares"`
+	PromptMiddlewares   []yokaimcpserver.MCPServerPromptMiddleware   `group:"mcp-server-prompt-middlewares"`
+	ResourceMiddlewares []yokaimcpserver.MCPServerResourceMiddleware `group:"mcp-server-resource-middlewares"`
 }
 
-func ProvideMCPServerRegistry(p ProvideMCPServerRegistryParams) *yokaimcpserver.MCPServerRegistry {
+func ProvideMCPServerRegistry(p ProvideMCPServerRegistryParams) (*yokaimcpserver.MCPServerRegistry, error) {
 	return yokaimcpserver.NewMCPServerRegistry(
 		p.Config,
 		p.Tools,
 		p.Prompts,
 		p.Resources,
 		p.ResourceTemplates,
+		p.ToolMiddlewares,
+		p.PromptMiddlewares,
+		p.ResourceMiddlewares,
 	)
 }
 
@@ -103,25 +185,49 @@ type ProvideMCPServerParam struct {
 	Provider yokaimcpserver.MCPServerHooksProvider
 	Factory  yokaimcpserver.MCPServerFactory
 	Registry *yokaimcpserver.MCPServerRegistry
+	Hooks    []yokaimcpserver.MCPServerHook `group:"mcp-server-hooks"`
 }
 
 func ProvideMCPServer(p ProvideMCPServerParam) *server.MCPServer {
-	srv := p.Factory.Create(server.WithHooks(p.Provider.Provide()))
+	options := []server.ServerOption{
+		server.WithHooks(yokaimcpserver.ComposeHooks(p.Provider, p.Hooks...)),
+	}
+
+	if p.Config.GetBool("modules.mcp.server.capabilities.completions") {
+		options = append(
+			options,
+			server.WithCompletions(),
+			server.WithPromptCompletionProvider(p.Registry),
+			server.WithResourceCompletionProvider(p.Registry),
+		)
+	}
+
+	srv := p.Factory.Create(options...)
 
 	p.Registry.Register(srv)
 
 	return srv
 }
 
+type ProvideDefaultMCPAuthenticatorParams struct {
+	fx.In
+	Config *config.Config
+}
+
+func ProvideDefaultMCPAuthenticator(p ProvideDefaultMCPAuthenticatorParams) (*auth.DefaultMCPAuthenticator, error) {
+	return auth.NewDefaultMCPAuthenticator(p.Config)
+}
+
 type ProvideDefaultMCPSSEContextHandlerParam struct {
 	fx.In
+	Config         *config.Config
 	Generator      uuid.UuidGenerator
 	TracerProvider oteltrace.TracerProvider
 	Logger         *log.Logger
 }
 
 func ProvideDefaultMCPSSEServerContextHandler(p ProvideDefaultMCPSSEContextHandlerParam) *sse.DefaultMCPSSEServerContextHandler {
-	return sse.NewDefaultMCPSSEServerContextHandler(p.Generator, p.TracerProvider, p.Logger)
+	return sse.NewDefaultMCPSSEServerContextHandler(p.Config, p.Generator, p.TracerProvider, p.Logger)
 }
 
 type ProvideDefaultMCPSSEServerFactoryParams struct {
@@ -142,15 +248,42 @@ type ProvideMCPSSEServerParam struct {
 	MCPServer                  *server.MCPServer
 	MCPSSEServerFactory        sse.MCPSSEServerFactory
 	MCPSSEServerContextHandler sse.MCPSSEServerContextHandler
+	Authenticator              auth.MCPAuthenticator
+	HttpServer                 *echo.Echo `optional:"true"`
 }
 
-func ProvideMCPSSEServer(p ProvideMCPSSEServerParam) *sse.MCPSSEServer {
+func ProvideMCPSSEServer(p ProvideMCPSSEServerParam) (*sse.MCPSSEServer, error) {
 	sseServer := p.MCPSSEServerFactory.Create(
 		p.MCPServer,
 		server.WithSSEContextFunc(p.MCPSSEServerContextHandler.Handle()),
 	)
 
+	if p.Config.GetBool("modules.mcp.server.transport.sse.auth.enabled") {
+		sseServer.UseAuthenticator(p.Authenticator)
+	}
+
+	if sseServer.Config().Mount && sseServer.Config().TLS.Enabled {
+		return nil, fmt.Errorf("cannot mount MCP SSE server with TLS: TLS must be configured on the HTTP server")
+	}
+
 	if p.Config.GetBool("modules.mcp.server.transport.sse.expose") {
+		if sseServer.Config().Mount {
+			if p.HttpServer == nil {
+				return nil, fmt.Errorf("cannot mount MCP SSE server: no HTTP server available")
+			}
+
+			sseServer.Mount(p.HttpServer)
+
+			// the HTTP server lifecycle is handled by its own module: only the SSE sessions are closed
+			p.LifeCycle.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					return sseServer.Stop(ctx)
+				},
+			})
+
+			return sseServer, nil
+		}
+
 		p.LifeCycle.Append(fx.Hook{
 			OnStart: func(context.Context) error {
 				go sseServer.Start(p.Context)
@@ -163,7 +296,117 @@ func ProvideMCPSSEServer(p ProvideMCPSSEServerParam) *sse.MCPSSEServer {
 		})
 	}
 
-	return sseServer
+	return sseServer, nil
+}
+
+type ProvideDefaultMCPStreamableHTTPContextHandlerParam struct {
+	fx.In
+	Config         *config.Config
+	Generator      uuid.UuidGenerator
+	TracerProvider oteltrace.TracerProvider
+	Logger         *log.Logger
+}
+
+func ProvideDefaultMCPStreamableHTTPServerContextHandler(p ProvideDefaultMCPStreamableHTTPContextHandlerParam) *streamablehttp.DefaultMCPStreamableHTTPServerContextHandler {
+	return streamablehttp.NewDefaultMCPStreamableHTTPServerContextHandler(p.Config, p.Generator, p.TracerProvider, p.Logger)
+}
+
+type ProvideDefaultMCPStreamableHTTPServerFactoryParams struct {
+	fx.In
+	Config *config.Config
+}
+
+func ProvideDefaultMCPStreamableHTTPServerFactory(p ProvideDefaultMCPStreamableHTTPServerFactoryParams) *streamablehttp.DefaultMCPStreamableHTTPServerFactory {
+	return streamablehttp.NewDefaultMCPStreamableHTTPServerFactory(p.Config)
+}
+
+type ProvideMCPStreamableHTTPServerParam struct {
+	fx.In
+	LifeCycle                             fx.Lifecycle
+	Context                               context.Context
+	Logger                                *log.Logger
+	Config                                *config.Config
+	MCPServer                             *server.MCPServer
+	MCPStreamableHTTPServerFactory        streamablehttp.MCPStreamableHTTPServerFactory
+	MCPStreamableHTTPServerContextHandler streamablehttp.MCPStreamableHTTPServerContextHandler
+}
+
+func ProvideMCPStreamableHTTPServer(p ProvideMCPStreamableHTTPServerParam) *streamablehttp.MCPStreamableHTTPServer {
+	streamableHTTPServer := p.MCPStreamableHTTPServerFactory.Create(
+		p.MCPServer,
+		server.WithHTTPContextFunc(p.MCPStreamableHTTPServerContextHandler.Handle()),
+	)
+
+	if p.Config.GetBool("modules.mcp.server.transport.streamable_http.expose") {
+		p.LifeCycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go streamableHTTPServer.Start(p.Context)
+
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return streamableHTTPServer.Stop(ctx)
+			},
+		})
+	}
+
+	return streamableHTTPServer
+}
+
+type ProvideDefaultMCPWebSocketContextHandlerParam struct {
+	fx.In
+	Config         *config.Config
+	Generator      uuid.UuidGenerator
+	TracerProvider oteltrace.TracerProvider
+	Logger         *log.Logger
+}
+
+func ProvideDefaultMCPWebSocketServerContextHandler(p ProvideDefaultMCPWebSocketContextHandlerParam) *websocket.DefaultMCPWebSocketServerContextHandler {
+	return websocket.NewDefaultMCPWebSocketServerContextHandler(p.Config, p.Generator, p.TracerProvider, p.Logger)
+}
+
+type ProvideDefaultMCPWebSocketServerFactoryParams struct {
+	fx.In
+	Config *config.Config
+}
+
+func ProvideDefaultMCPWebSocketServerFactory(p ProvideDefaultMCPWebSocketServerFactoryParams) *websocket.DefaultMCPWebSocketServerFactory {
+	return websocket.NewDefaultMCPWebSocketServerFactory(p.Config)
+}
+
+type ProvideMCPWebSocketServerParam struct {
+	fx.In
+	LifeCycle                        fx.Lifecycle
+	Context                          context.Context
+	Logger                           *log.Logger
+	Config                           *config.Config
+	MCPServer                        *server.MCPServer
+	MCPWebSocketServerFactory        websocket.MCPWebSocketServerFactory
+	MCPWebSocketServerContextHandler websocket.MCPWebSocketServerContextHandler
+	Generator                        uuid.UuidGenerator
+}
+
+func ProvideMCPWebSocketServer(p ProvideMCPWebSocketServerParam) *websocket.MCPWebSocketServer {
+	webSocketServer := p.MCPWebSocketServerFactory.Create(
+		p.MCPServer,
+		websocket.WithWebSocketContextFunc(p.MCPWebSocketServerContextHandler.Handle()),
+		websocket.WithSessionIDGenerator(p.Generator),
+	)
+
+	if p.Config.GetBool("modules.mcp.server.transport.websocket.expose") {
+		p.LifeCycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go webSocketServer.Start(p.Context)
+
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return webSocketServer.Stop(ctx)
+			},
+		})
+	}
+
+	return webSocketServer
 }
 
 type ProvideDefaultMCPStdioContextHandlerParam struct {
@@ -0,0 +1,56 @@
+package mcp_test
+
+import (
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestProvideMCPServerMetrics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		backend  string
+		expected any
+		err      string
+	}{
+		{"default", "", &yokaimcpserver.PrometheusMCPServerMetrics{}, ""},
+		{"prometheus", yokaimcpserver.MetricsBackendPrometheus, &yokaimcpserver.PrometheusMCPServerMetrics{}, ""},
+		{"otel", yokaimcpserver.MetricsBackendOTel, &yokaimcpserver.OTelMCPServerMetrics{}, ""},
+		{"invalid", "statsd", nil, `invalid MCP server metrics backend "statsd"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			metrics, err := mcp.ProvideMCPServerMetrics(mcp.ProvideMCPServerMetricsParams{
+				Config: mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      metrics:
+        backend: "`+tt.backend+`"
+`),
+				Registry:      prometheus.NewRegistry(),
+				MeterProvider: noop.NewMeterProvider(),
+			})
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, metrics)
+		})
+	}
+}
@@ -84,3 +84,83 @@ func AsMCPServerResourceTemplates(constructors ...any) fx.Option {
 
 	return fx.Options(options...)
 }
+
+func AsMCPServerToolMiddleware(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(server.MCPServerToolMiddleware)),
+			fx.ResultTags(`group:"mcp-server-tool-middlewares"`),
+		),
+	)
+}
+
+func AsMCPServerToolMiddlewares(constructors ...any) fx.Option {
+	options := []fx.Option{}
+
+	for _, constructor := range constructors {
+		options = append(options, AsMCPServerToolMiddleware(constructor))
+	}
+
+	return fx.Options(options...)
+}
+
+func AsMCPServerPromptMiddleware(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(server.MCPServerPromptMiddleware)),
+			fx.ResultTags(`group:"mcp-server-prompt-middlewares"`),
+		),
+	)
+}
+
+func AsMCPServerPromptMiddlewares(constructors ...any) fx.Option {
+	options := []fx.Option{}
+
+	for _, constructor := range constructors {
+		options = append(options, AsMCPServerPromptMiddleware(constructor))
+	}
+
+	return fx.Options(options...)
+}
+
+func AsMCPServerResourceMiddleware(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(server.MCPServerResourceMiddleware)),
+			fx.ResultTags(`group:"mcp-server-resource-middlewares"`),
+		),
+	)
+}
+
+func AsMCPServerResourceMiddlewares(constructors ...any) fx.Option {
+	options := []fx.Option{}
+
+	for _, constructor := range constructors {
+		options = append(options, AsMCPServerResourceMiddleware(constructor))
+	}
+
+	return fx.Options(options...)
+}
+
+func AsMCPServerHook(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(server.MCPServerHook)),
+			fx.ResultTags(`group:"mcp-server-hooks"`),
+		),
+	)
+}
+
+func AsMCPServerHooks(constructors ...any) fx.Option {
+	options := []fx.Option{}
+
+	for _, constructor := range constructors {
+		options = append(options, AsMCPServerHook(constructor))
+	}
+
+	return fx.Options(options...)
+}
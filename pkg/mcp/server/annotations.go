@@ -0,0 +1,49 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cast"
+)
+
+// ApplyToolAnnotationsConfig overrides the annotations of a given mcp.Tool with the ones configured under
+// modules.mcp.server.tools.annotations.<tool name>: title, read_only, destructive, idempotent and open_world.
+//
+// The overrides are looked up in the annotations map rather than by key path, since tool names can contain dots.
+func ApplyToolAnnotationsConfig(config *config.Config, tool *mcp.Tool) {
+	annotations := config.GetStringMap("modules.mcp.server.tools.annotations")
+
+	overrides, ok := annotations[strings.ToLower(tool.Name)]
+	if !ok {
+		return
+	}
+
+	override := cast.ToStringMap(overrides)
+
+	if value, ok := override["title"]; ok {
+		tool.Annotations.Title = cast.ToString(value)
+	}
+
+	if value, ok := override["read_only"]; ok {
+		tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(cast.ToBool(value))
+	}
+
+	if value, ok := override["destructive"]; ok {
+		tool.Annotations.DestructiveHint = mcp.ToBoolPtr(cast.ToBool(value))
+	}
+
+	if value, ok := override["idempotent"]; ok {
+		tool.Annotations.IdempotentHint = mcp.ToBoolPtr(cast.ToBool(value))
+	}
+
+	if value, ok := override["open_world"]; ok {
+		tool.Annotations.OpenWorldHint = mcp.ToBoolPtr(cast.ToBool(value))
+	}
+}
+
+// IsReadOnlyTool returns true if a given mcp.Tool is annotated as read-only.
+func IsReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyToolAnnotationsConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      tools:
+        annotations:
+          book-list:
+            title: Configured title
+            read_only: true
+            destructive: false
+            idempotent: true
+            open_world: false
+          books.Delete:
+            title: Configured delete title
+            destructive: true
+`)
+
+	tool := mcp.NewTool("book-list", mcp.WithTitleAnnotation("Original title"), mcp.WithReadOnlyHintAnnotation(false))
+	yokaimcpserver.ApplyToolAnnotationsConfig(cfg, &tool)
+
+	assert.Equal(t, "Configured title", tool.Annotations.Title)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.False(t, *tool.Annotations.DestructiveHint)
+	assert.True(t, *tool.Annotations.IdempotentHint)
+	assert.False(t, *tool.Annotations.OpenWorldHint)
+	assert.True(t, yokaimcpserver.IsReadOnlyTool(tool))
+
+	other := mcp.NewTool("book-delete", mcp.WithTitleAnnotation("Original title"))
+	yokaimcpserver.ApplyToolAnnotationsConfig(cfg, &other)
+
+	assert.Equal(t, "Original title", other.Annotations.Title)
+	assert.False(t, yokaimcpserver.IsReadOnlyTool(other))
+
+	dotted := mcp.NewTool("books.Delete", mcp.WithTitleAnnotation("Original title"))
+	yokaimcpserver.ApplyToolAnnotationsConfig(cfg, &dotted)
+
+	assert.Equal(t, "Configured delete title", dotted.Annotations.Title)
+	assert.True(t, *dotted.Annotations.DestructiveHint)
+	assert.False(t, yokaimcpserver.IsReadOnlyTool(dotted))
+}
+
+func TestMCPServerRegistryReadOnlyMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      read_only: true
+      capabilities:
+        tools: true
+      tools:
+        annotations:
+          book-get:
+            read_only: true
+`)
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		cfg,
+		[]yokaimcpserver.MCPServerTool{
+			newTestTool("book-list", mcp.WithReadOnlyHintAnnotation(true)),
+			newTestTool("book-get"),
+			newTestTool("book-delete", mcp.WithReadOnlyHintAnnotation(false)),
+		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	registry.Register(mcpServer)
+
+	info := registry.Info()
+	assert.True(t, info.ReadOnly)
+	assert.Contains(t, info.Registrations.Tools, "book-list")
+	assert.Contains(t, info.Registrations.Tools, "book-get")
+	assert.Equal(t, "read only mode, tool not annotated as read-only", info.Filtered.Tools["book-delete"])
+
+	assert.NotNil(t, mcpServer.GetTool("book-get"))
+	assert.Nil(t, mcpServer.GetTool("book-delete"))
+
+	err = registry.AddTool(newTestTool("book-update"))
+	assert.ErrorIs(t, err, yokaimcpserver.ErrFiltered)
+	assert.EqualError(t, err, `MCP tool "book-update" is filtered out (read only mode, tool not annotated as read-only)`)
+	assert.Nil(t, mcpServer.GetTool("book-update"))
+}
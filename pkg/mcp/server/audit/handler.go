@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandlerPath is the HTTP path of the MCPAuditHandler.
+const HandlerPath = "/mcp/audit"
+
+// MCPAuditHandler is a HTTP handler to browse the audit trail entries, filtered by the tool, session, principal,
+// outcome, limit and offset query parameters.
+type MCPAuditHandler struct {
+	repository *MCPAuditRepository
+}
+
+// NewMCPAuditHandler returns a new MCPAuditHandler.
+func NewMCPAuditHandler(repository *MCPAuditRepository) *MCPAuditHandler {
+	return &MCPAuditHandler{
+		repository: repository,
+	}
+}
+
+// Handle handles HTTP requests.
+func (h *MCPAuditHandler) Handle() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		limit, err := queryParamInt(c, "limit")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+
+		offset, err := queryParamInt(c, "offset")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid offset")
+		}
+
+		entries, err := h.repository.List(c.Request().Context(), MCPAuditListParams{
+			Tool:      c.QueryParam("tool"),
+			SessionID: c.QueryParam("session"),
+			Principal: c.QueryParam("principal"),
+			Outcome:   c.QueryParam("outcome"),
+			Limit:     limit,
+			Offset:    offset,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(http.StatusOK, entries)
+	}
+}
+
+func queryParamInt(c echo.Context, name string) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
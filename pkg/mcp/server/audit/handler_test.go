@@ -0,0 +1,58 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPAuditHandler(t *testing.T) {
+	t.Parallel()
+
+	repository := newTestRepository(t)
+
+	for _, tool := range []string{"book-list", "book-create", "book-create"} {
+		require.NoError(t, repository.Insert(context.Background(), audit.MCPAuditEntry{Time: time.Now(), Tool: tool}))
+	}
+
+	e := echo.New()
+	e.GET(audit.HandlerPath, audit.NewMCPAuditHandler(repository).Handle())
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		count  int
+	}{
+		{"all", "", http.StatusOK, 3},
+		{"filtered by tool", "?tool=book-create", http.StatusOK, 2},
+		{"limited", "?limit=1&offset=2", http.StatusOK, 1},
+		{"invalid limit", "?limit=abc", http.StatusBadRequest, 0},
+		{"invalid offset", "?offset=abc", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, audit.HandlerPath+tt.query, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var entries []audit.MCPAuditEntry
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+			assert.Len(t, entries, tt.count)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/ankorstore/yokai/config"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+const auditConfig = `
+modules:
+  mcp:
+    server:
+      audit:
+        enabled: true
+        buffer_size: 10
+        resource:
+          enabled: true
+          limit: 10
+`
+
+// newTestRepository returns a MCPAuditRepository backed by an in memory SQLite database.
+func newTestRepository(tb testing.TB) *audit.MCPAuditRepository {
+	tb.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(tb, err)
+
+	db.SetMaxOpenConns(1)
+
+	tb.Cleanup(func() {
+		require.NoError(tb, db.Close())
+	})
+
+	_, err = db.Exec(`
+		CREATE TABLE mcp_audit_entries (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			time       DATETIME NOT NULL,
+			session_id VARCHAR(255) NOT NULL,
+			request_id VARCHAR(255) NOT NULL,
+			transport  VARCHAR(64) NOT NULL,
+			client     VARCHAR(255) NOT NULL,
+			principal  VARCHAR(255) NOT NULL,
+			tool       VARCHAR(128) NOT NULL,
+			arguments  TEXT NOT NULL,
+			outcome    VARCHAR(32) NOT NULL,
+			error      TEXT NOT NULL,
+			latency_ms BIGINT NOT NULL
+		)
+	`)
+	require.NoError(tb, err)
+
+	return audit.NewMCPAuditRepository(db)
+}
+
+// newTestMCPServer returns a MCP server with the audit hooks registered, exposing a tool handled by a given handler.
+func newTestMCPServer(tb testing.TB, hook *audit.MCPAuditHook, handler server.ToolHandlerFunc) *server.MCPServer {
+	tb.Helper()
+
+	hooks := &server.Hooks{}
+	hook.Register(hooks)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks), server.WithToolCapabilities(false))
+	mcpServer.AddTool(mcp.NewTool("book-create", mcp.WithString("title"), mcp.WithString("password")), handler)
+
+	return mcpServer
+}
+
+func newTestRedactor(tb testing.TB, cfg *config.Config) *yokaimcpserver.MCPServerRedactor {
+	tb.Helper()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(cfg)
+	require.NoError(tb, err)
+
+	return redactor
+}
+
+// callTool calls a given tool with given arguments on a given MCP server.
+func callTool(tb testing.TB, mcpServer *server.MCPServer, id int, name string, args map[string]any) {
+	tb.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  mcp.MethodToolsCall,
+		"params": map[string]any{
+			"name":      name,
+			"arguments": args,
+		},
+	})
+	require.NoError(tb, err)
+
+	require.NotNil(tb, mcpServer.HandleMessage(context.Background(), message))
+}
@@ -0,0 +1,223 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/log"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	OutcomeSuccess   = "success"
+	OutcomeToolError = "tool_error"
+	OutcomeError     = "error"
+)
+
+const (
+	DefaultBufferSize    = 1000
+	DefaultInsertTimeout = 5 * time.Second
+)
+
+var _ yokaimcpserver.MCPServerHook = (*MCPAuditHook)(nil)
+
+// MCPAuditHook records an audit trail entry for each MCP tool invocation, with its redacted arguments.
+//
+// Entries are buffered (modules.mcp.server.audit.buffer_size, DefaultBufferSize if not set) and persisted in
+// background once started, so the response path never waits on the database: entries are dropped when the buffer
+// is full.
+type MCPAuditHook struct {
+	config     *config.Config
+	repository *MCPAuditRepository
+	redactor   *yokaimcpserver.MCPServerRedactor
+	starts     sync.Map
+	entries    chan MCPAuditEntry
+	timeout    time.Duration
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewMCPAuditHook returns a new MCPAuditHook.
+func NewMCPAuditHook(
+	config *config.Config,
+	repository *MCPAuditRepository,
+	redactor *yokaimcpserver.MCPServerRedactor,
+) *MCPAuditHook {
+	bufferSize := config.GetInt("modules.mcp.server.audit.buffer_size")
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	timeout := config.GetDuration("modules.mcp.server.audit.insert_timeout")
+	if timeout <= 0 {
+		timeout = DefaultInsertTimeout
+	}
+
+	return &MCPAuditHook{
+		config:     config,
+		repository: repository,
+		redactor:   redactor,
+		entries:    make(chan MCPAuditEntry, bufferSize),
+		timeout:    timeout,
+	}
+}
+
+// Enabled returns true if the audit is enabled in modules.mcp.server.audit.enabled.
+func (h *MCPAuditHook) Enabled() bool {
+	return h.config.GetBool("modules.mcp.server.audit.enabled")
+}
+
+// Start starts persisting the buffered entries in background, each insert bounded by
+// modules.mcp.server.audit.insert_timeout (DefaultInsertTimeout if not set).
+func (h *MCPAuditHook) Start(ctx context.Context) {
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+
+	go func() {
+		defer close(h.done)
+
+		for {
+			select {
+			case entry := <-h.entries:
+				h.insert(ctx, entry)
+			case <-h.stop:
+				h.flush(ctx)
+
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop persists the remaining buffered entries, and stops.
+func (h *MCPAuditHook) Stop() {
+	if h.stop == nil {
+		return
+	}
+
+	close(h.stop)
+	<-h.done
+
+	h.stop = nil
+}
+
+// Register registers the audit hooks, if enabled in modules.mcp.server.audit.enabled.
+func (h *MCPAuditHook) Register(hooks *server.Hooks) {
+	if !h.Enabled() {
+		return
+	}
+
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		h.starts.Store(requestKey(ctx, id), time.Now())
+	})
+
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result any) {
+		outcome := OutcomeSuccess
+
+		errMessage, isToolErr := yokaimcpserver.ToolResultError(result)
+		if isToolErr {
+			outcome = OutcomeToolError
+		}
+
+		h.record(ctx, id, message, outcome, errMessage)
+	})
+
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		if method != mcp.MethodToolsCall {
+			return
+		}
+
+		request, ok := message.(*mcp.CallToolRequest)
+		if !ok {
+			return
+		}
+
+		h.record(ctx, id, request, OutcomeError, fmt.Sprintf("%v", err))
+	})
+}
+
+func (h *MCPAuditHook) record(ctx context.Context, id any, request *mcp.CallToolRequest, outcome string, errMessage string) {
+	entry := MCPAuditEntry{
+		Time:      time.Now(),
+		SessionID: yokaimcpserver.SessionID(ctx),
+		RequestID: yokaimcpservercontext.CtxRequestId(ctx),
+		Principal: yokaimcpservercontext.CtxPrincipal(ctx),
+		Tool:      request.Params.Name,
+		Arguments: h.arguments(request),
+		Outcome:   outcome,
+		Error:     h.redactor.RedactString(errMessage),
+	}
+
+	if start, ok := h.starts.LoadAndDelete(requestKey(ctx, id)); ok {
+		//nolint:forcetypeassert
+		entry.LatencyMs = time.Since(start.(time.Time)).Milliseconds()
+	}
+
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		entry.Transport = yokaimcpserver.SessionTransport(session)
+
+		if sessionWithClientInfo, ok := session.(server.SessionWithClientInfo); ok {
+			entry.Client = sessionWithClientInfo.GetClientInfo().Name
+		}
+	}
+
+	select {
+	case h.entries <- entry:
+	default:
+		log.CtxLogger(ctx).Warn().Str("mcpTool", entry.Tool).Msg("dropped MCP audit entry: buffer full")
+	}
+}
+
+func (h *MCPAuditHook) flush(ctx context.Context) {
+	for {
+		select {
+		case entry := <-h.entries:
+			h.insert(ctx, entry)
+		default:
+			return
+		}
+	}
+}
+
+func (h *MCPAuditHook) insert(ctx context.Context, entry MCPAuditEntry) {
+	insertCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.timeout)
+	defer cancel()
+
+	err := h.repository.Insert(insertCtx, entry)
+	if err != nil {
+		log.CtxLogger(ctx).Error().Err(err).Str("mcpTool", entry.Tool).Msg("failed to record MCP audit entry")
+	}
+}
+
+// arguments returns the redacted JSON arguments of a given tools/call request.
+func (h *MCPAuditHook) arguments(request *mcp.CallToolRequest) string {
+	data, err := h.redactor.Marshal(request)
+	if err != nil {
+		return ""
+	}
+
+	var redacted struct {
+		Params struct {
+			Arguments json.RawMessage `json:"arguments"`
+		} `json:"params"`
+	}
+
+	if err = json.Unmarshal(data, &redacted); err != nil || redacted.Params.Arguments == nil {
+		return ""
+	}
+
+	return string(redacted.Params.Arguments)
+}
+
+func requestKey(ctx context.Context, id any) string {
+	return fmt.Sprintf("%s:%v", yokaimcpserver.SessionID(ctx), id)
+}
@@ -0,0 +1,125 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPAuditHookRecordsInBackground(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, auditConfig)
+	repository := newTestRepository(t)
+
+	hook := audit.NewMCPAuditHook(cfg, repository, newTestRedactor(t, cfg))
+	assert.True(t, hook.Enabled())
+
+	mcpServer := newTestMCPServer(t, hook, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.GetString("title", "") {
+		case "tool-error":
+			return mcp.NewToolResultError("invalid title"), nil
+		case "error":
+			return nil, errors.New("failure")
+		default:
+			return mcp.NewToolResultText("created"), nil
+		}
+	})
+
+	callTool(t, mcpServer, 1, "book-create", map[string]any{"title": "success", "password": "secret"})
+	callTool(t, mcpServer, 2, "book-create", map[string]any{"title": "tool-error"})
+
+	// buffered until started
+	entries, err := repository.List(context.Background(), audit.MCPAuditListParams{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	hook.Start(context.Background())
+
+	callTool(t, mcpServer, 3, "book-create", map[string]any{"title": "error"})
+
+	hook.Stop()
+
+	entries, err = repository.List(context.Background(), audit.MCPAuditListParams{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, audit.OutcomeError, entries[0].Outcome)
+	assert.Equal(t, "request error: failure", entries[0].Error)
+
+	assert.Equal(t, audit.OutcomeToolError, entries[1].Outcome)
+	assert.Equal(t, "invalid title", entries[1].Error)
+
+	assert.Equal(t, audit.OutcomeSuccess, entries[2].Outcome)
+	assert.Equal(t, "book-create", entries[2].Tool)
+	assert.Equal(t, "", entries[2].Error)
+	assert.JSONEq(t, `{"title":"success","password":"[REDACTED]"}`, entries[2].Arguments)
+}
+
+func TestMCPAuditHookDropsWhenBufferFull(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      audit:
+        enabled: true
+        buffer_size: 2
+`)
+	repository := newTestRepository(t)
+
+	hook := audit.NewMCPAuditHook(cfg, repository, newTestRedactor(t, cfg))
+
+	mcpServer := newTestMCPServer(t, hook, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("created"), nil
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := range 5 {
+			callTool(t, mcpServer, i, "book-create", map[string]any{"title": "test"})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool calls blocked by the audit")
+	}
+
+	hook.Start(context.Background())
+	hook.Stop()
+
+	entries, err := repository.List(context.Background(), audit.MCPAuditListParams{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestMCPAuditHookDisabled(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, "app:\n  name: test\n")
+
+	hook := audit.NewMCPAuditHook(cfg, nil, newTestRedactor(t, cfg))
+	assert.False(t, hook.Enabled())
+
+	hooks := &server.Hooks{}
+	hook.Register(hooks)
+
+	assert.Empty(t, hooks.OnAfterCallTool)
+	assert.Empty(t, hooks.OnError)
+
+	hook.Stop()
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/log"
+)
+
+const DefaultPruneInterval = time.Hour
+
+// MCPAuditPruner periodically deletes the audit trail entries older than modules.mcp.server.audit.retention.
+type MCPAuditPruner struct {
+	config     *config.Config
+	repository *MCPAuditRepository
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewMCPAuditPruner returns a new MCPAuditPruner.
+func NewMCPAuditPruner(config *config.Config, repository *MCPAuditRepository) *MCPAuditPruner {
+	return &MCPAuditPruner{
+		config:     config,
+		repository: repository,
+	}
+}
+
+// Enabled returns true if the audit is enabled with a retention.
+func (p *MCPAuditPruner) Enabled() bool {
+	return p.config.GetBool("modules.mcp.server.audit.enabled") &&
+		p.config.GetDuration("modules.mcp.server.audit.retention") > 0
+}
+
+// Start starts pruning in background, every modules.mcp.server.audit.prune_interval (DefaultPruneInterval if not set).
+func (p *MCPAuditPruner) Start(ctx context.Context) {
+	interval := p.config.GetDuration("modules.mcp.server.audit.prune_interval")
+	if interval <= 0 {
+		interval = DefaultPruneInterval
+	}
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			p.Prune(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops pruning.
+func (p *MCPAuditPruner) Stop() {
+	if p.stop == nil {
+		return
+	}
+
+	close(p.stop)
+	<-p.done
+
+	p.stop = nil
+}
+
+// Prune deletes the audit trail entries older than the retention.
+func (p *MCPAuditPruner) Prune(ctx context.Context) {
+	retention := p.config.GetDuration("modules.mcp.server.audit.retention")
+
+	deleted, err := p.repository.Prune(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.CtxLogger(ctx).Error().Err(err).Msg("failed to prune MCP audit entries")
+
+		return
+	}
+
+	log.CtxLogger(ctx).Debug().Int("deleted", deleted).Msg("pruned MCP audit entries")
+}
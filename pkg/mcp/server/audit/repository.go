@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+const (
+	Table            = "mcp_audit_entries"
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// MCPAuditEntry is an audit trail entry of a MCP tool invocation.
+type MCPAuditEntry struct {
+	ID        int64     `json:"id"`
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"sessionId"`
+	RequestID string    `json:"requestId"`
+	Transport string    `json:"transport"`
+	Client    string    `json:"client"`
+	Principal string    `json:"principal"`
+	Tool      string    `json:"tool"`
+	Arguments string    `json:"arguments"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error"`
+	LatencyMs int64     `json:"latencyMs"`
+}
+
+// MCPAuditListParams are the parameters to list audit trail entries, most recent first.
+type MCPAuditListParams struct {
+	Tool      string
+	SessionID string
+	Principal string
+	Outcome   string
+	Limit     int
+	Offset    int
+}
+
+// MCPAuditRepository persists the audit trail entries in SQL, in the Table created by the migrations.
+type MCPAuditRepository struct {
+	db *sql.DB
+}
+
+// NewMCPAuditRepository returns a new MCPAuditRepository.
+func NewMCPAuditRepository(db *sql.DB) *MCPAuditRepository {
+	return &MCPAuditRepository{
+		db: db,
+	}
+}
+
+// Insert persists a given audit trail entry.
+func (r *MCPAuditRepository) Insert(ctx context.Context, entry MCPAuditEntry) error {
+	ib := sqlbuilder.NewInsertBuilder()
+	ib.InsertInto(Table)
+	ib.Cols(
+		"time",
+		"session_id",
+		"request_id",
+		"transport",
+		"client",
+		"principal",
+		"tool",
+		"arguments",
+		"outcome",
+		"error",
+		"latency_ms",
+	)
+	ib.Values(
+		entry.Time,
+		entry.SessionID,
+		entry.RequestID,
+		entry.Transport,
+		entry.Client,
+		entry.Principal,
+		entry.Tool,
+		entry.Arguments,
+		entry.Outcome,
+		entry.Error,
+		entry.LatencyMs,
+	)
+	query, args := ib.Build()
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// List returns the audit trail entries matching given params, most recent first.
+func (r *MCPAuditRepository) List(ctx context.Context, params MCPAuditListParams) ([]MCPAuditEntry, error) {
+	entries := []MCPAuditEntry{}
+
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.Select(
+		"id",
+		"time",
+		"session_id",
+		"request_id",
+		"transport",
+		"client",
+		"principal",
+		"tool",
+		"arguments",
+		"outcome",
+		"error",
+		"latency_ms",
+	)
+	sb.From(Table)
+	sb.OrderBy("id DESC")
+
+	if params.Tool != "" {
+		sb.Where(sb.Equal("tool", params.Tool))
+	}
+
+	if params.SessionID != "" {
+		sb.Where(sb.Equal("session_id", params.SessionID))
+	}
+
+	if params.Principal != "" {
+		sb.Where(sb.Equal("principal", params.Principal))
+	}
+
+	if params.Outcome != "" {
+		sb.Where(sb.Equal("outcome", params.Outcome))
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+
+	sb.Limit(min(limit, MaxListLimit))
+
+	if params.Offset > 0 {
+		sb.Offset(params.Offset)
+	}
+
+	query, args := sb.Build()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry MCPAuditEntry
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Time,
+			&entry.SessionID,
+			&entry.RequestID,
+			&entry.Transport,
+			&entry.Client,
+			&entry.Principal,
+			&entry.Tool,
+			&entry.Arguments,
+			&entry.Outcome,
+			&entry.Error,
+			&entry.LatencyMs,
+		); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Prune deletes the audit trail entries older than a given time, and returns the number of deleted entries.
+func (r *MCPAuditRepository) Prune(ctx context.Context, before time.Time) (int, error) {
+	db := sqlbuilder.NewDeleteBuilder()
+	db.DeleteFrom(Table)
+	db.Where(db.LessThan("time", before))
+
+	query, args := db.Build()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	return int(rowsAffected), err
+}
@@ -0,0 +1,57 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPAuditRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repository := newTestRepository(t)
+
+	now := time.Now().UTC()
+
+	for _, entry := range []audit.MCPAuditEntry{
+		{Time: now.Add(-48 * time.Hour), Tool: "book-list", SessionID: "s1", Principal: "alice", Outcome: audit.OutcomeSuccess},
+		{Time: now, Tool: "book-create", SessionID: "s1", Principal: "alice", Outcome: audit.OutcomeToolError},
+		{Time: now, Tool: "book-create", SessionID: "s2", Principal: "bob", Outcome: audit.OutcomeSuccess},
+	} {
+		require.NoError(t, repository.Insert(ctx, entry))
+	}
+
+	entries, err := repository.List(ctx, audit.MCPAuditListParams{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "bob", entries[0].Principal)
+
+	entries, err = repository.List(ctx, audit.MCPAuditListParams{Tool: "book-create", Principal: "alice"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.OutcomeToolError, entries[0].Outcome)
+
+	entries, err = repository.List(ctx, audit.MCPAuditListParams{SessionID: "s1", Outcome: audit.OutcomeSuccess})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "book-list", entries[0].Tool)
+
+	entries, err = repository.List(ctx, audit.MCPAuditListParams{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "alice", entries[0].Principal)
+	assert.Equal(t, "book-create", entries[0].Tool)
+
+	deleted, err := repository.Prune(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	entries, err = repository.List(ctx, audit.MCPAuditListParams{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"fmt"
+
+	"github.com/ankorstore/yokai/config"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ResourceName = "mcp-audit"
+	ResourceURI  = "audit://tool-calls"
+)
+
+var _ yokaimcpserver.MCPServerResource = (*MCPAuditResource)(nil)
+
+// MCPAuditResource is a read-only MCP resource exposing the most recent audit trail entries of the authenticated
+// caller: reads without principal are rejected.
+type MCPAuditResource struct {
+	config     *config.Config
+	repository *MCPAuditRepository
+}
+
+// NewMCPAuditResource returns a new MCPAuditResource.
+func NewMCPAuditResource(config *config.Config, repository *MCPAuditRepository) *MCPAuditResource {
+	return &MCPAuditResource{
+		config:     config,
+		repository: repository,
+	}
+}
+
+func (r *MCPAuditResource) Name() string {
+	return ResourceName
+}
+
+func (r *MCPAuditResource) URI() string {
+	return ResourceURI
+}
+
+func (r *MCPAuditResource) Options() []mcp.ResourceOption {
+	return []mcp.ResourceOption{
+		mcp.WithResourceDescription("Most recent MCP tool invocations audit trail entries of the caller"),
+		mcp.WithMIMEType("application/json"),
+	}
+}
+
+func (r *MCPAuditResource) Handle() server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		principal := yokaimcpservercontext.CtxPrincipal(ctx)
+		if principal == "" {
+			return nil, fmt.Errorf("cannot read MCP audit resource: %w", auth.ErrUnauthenticated)
+		}
+
+		entries, err := r.repository.List(ctx, MCPAuditListParams{
+			Limit:     r.config.GetInt("modules.mcp.server.audit.resource.limit"),
+			Principal: principal,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
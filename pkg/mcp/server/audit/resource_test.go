@@ -0,0 +1,56 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/audit"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPAuditResource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repository := newTestRepository(t)
+
+	for range 12 {
+		require.NoError(t, repository.Insert(ctx, audit.MCPAuditEntry{Time: time.Now(), Principal: "alice", Tool: "book-list"}))
+	}
+
+	require.NoError(t, repository.Insert(ctx, audit.MCPAuditEntry{Time: time.Now(), Principal: "bob", Tool: "book-list"}))
+
+	resource := audit.NewMCPAuditResource(mcptest.NewConfig(t, auditConfig), repository)
+	assert.Equal(t, audit.ResourceName, resource.Name())
+	assert.Equal(t, audit.ResourceURI, resource.URI())
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = audit.ResourceURI
+
+	_, err := resource.Handle()(ctx, request)
+	require.ErrorIs(t, err, auth.ErrUnauthenticated)
+
+	contents, err := resource.Handle()(yokaimcpservercontext.WithPrincipal(ctx, "alice"), request)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+
+	text, ok := contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, "application/json", text.MIMEType)
+
+	var entries []audit.MCPAuditEntry
+	require.NoError(t, json.Unmarshal([]byte(text.Text), &entries))
+	assert.Len(t, entries, 10)
+	assert.Equal(t, int64(12), entries[0].ID)
+
+	for _, entry := range entries {
+		assert.Equal(t, "alice", entry.Principal)
+	}
+}
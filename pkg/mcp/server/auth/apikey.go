@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"net/http"
+)
+
+var _ MCPAuthenticator = (*APIKeyAuthenticator)(nil)
+
+// APIKeyAuthenticator authenticates requests from an API key header, against static keys indexed by principal.
+type APIKeyAuthenticator struct {
+	header string
+	keys   map[string]string
+}
+
+// NewAPIKeyAuthenticator returns a new APIKeyAuthenticator.
+func NewAPIKeyAuthenticator(header string, keys map[string]string) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		header: header,
+		keys:   keys,
+	}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (string, error) {
+	key := r.Header.Get(a.header)
+	if key == "" {
+		return "", ErrUnauthenticated
+	}
+
+	principal, ok := match(a.keys, key)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/log"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+)
+
+const DefaultAPIKeyHeader = "X-API-Key"
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// MCPAuthenticator authenticates the HTTP requests of MCP clients, returning the principal of the caller.
+type MCPAuthenticator interface {
+	Authenticate(r *http.Request) (string, error)
+}
+
+var _ MCPAuthenticator = (*DefaultMCPAuthenticator)(nil)
+
+// DefaultMCPAuthenticator tries the static bearer tokens, then the API keys, configured in
+// modules.mcp.server.transport.sse.auth.
+type DefaultMCPAuthenticator struct {
+	authenticators []MCPAuthenticator
+}
+
+// MCPPrincipalCredential is a configured principal, with its bearer token or API key.
+type MCPPrincipalCredential struct {
+	Name  string `mapstructure:"name"`
+	Token string `mapstructure:"token"`
+	Key   string `mapstructure:"key"`
+}
+
+// NewDefaultMCPAuthenticator returns a new DefaultMCPAuthenticator.
+//
+// The principals are configured as lists of {name, token} bearer tokens and {name, key} API keys entries, since the
+// keys of configured maps are lowercased.
+func NewDefaultMCPAuthenticator(config *config.Config) (*DefaultMCPAuthenticator, error) {
+	apiKeyHeader := config.GetString("modules.mcp.server.transport.sse.auth.api_key.header")
+	if apiKeyHeader == "" {
+		apiKeyHeader = DefaultAPIKeyHeader
+	}
+
+	tokens, err := principalCredentials(config, "modules.mcp.server.transport.sse.auth.bearer.tokens", func(c MCPPrincipalCredential) string {
+		return c.Token
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := principalCredentials(config, "modules.mcp.server.transport.sse.auth.api_key.keys", func(c MCPPrincipalCredential) string {
+		return c.Key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DefaultMCPAuthenticator{
+		authenticators: []MCPAuthenticator{
+			NewStaticBearerTokenAuthenticator(tokens),
+			NewAPIKeyAuthenticator(apiKeyHeader, keys),
+		},
+	}, nil
+}
+
+func (a *DefaultMCPAuthenticator) Authenticate(r *http.Request) (string, error) {
+	for _, authenticator := range a.authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err == nil {
+			return principal, nil
+		}
+	}
+
+	return "", ErrUnauthenticated
+}
+
+// Middleware rejects the requests not authenticated by a given MCPAuthenticator with a 401, and stores the principal
+// of the authenticated ones in their context.
+func Middleware(authenticator MCPAuthenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			log.CtxLogger(r.Context()).Warn().
+				Err(err).
+				Str("system", "mcpserver").
+				Str("remoteAddr", r.RemoteAddr).
+				Str("path", r.URL.Path).
+				Msg("rejected unauthenticated MCP request")
+
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(yokaimcpservercontext.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// principalCredentials returns the credentials indexed by principal, configured as a list of entries at a given key.
+func principalCredentials(
+	config *config.Config,
+	key string,
+	credential func(MCPPrincipalCredential) string,
+) (map[string]string, error) {
+	if _, ok := config.Get(key).(map[string]any); ok {
+		return nil, fmt.Errorf("invalid MCP principals in %s: expected a list of entries", key)
+	}
+
+	var entries []MCPPrincipalCredential
+
+	if err := config.UnmarshalKey(key, &entries); err != nil {
+		return nil, fmt.Errorf("invalid MCP principals in %s: %w", key, err)
+	}
+
+	credentials := make(map[string]string, len(entries))
+	principals := make(map[string]string, len(entries))
+
+	for i, entry := range entries {
+		if entry.Name == "" || credential(entry) == "" {
+			return nil, fmt.Errorf("invalid MCP principal at %s[%d]: name and credential are required", key, i)
+		}
+
+		if _, ok := credentials[entry.Name]; ok {
+			return nil, fmt.Errorf("duplicate MCP principal %q in %s", entry.Name, key)
+		}
+
+		// a shared credential would resolve to any of its principals
+		if principal, ok := principals[credential(entry)]; ok {
+			return nil, fmt.Errorf("duplicate MCP credential in %s: shared by principals %q and %q", key, principal, entry.Name)
+		}
+
+		credentials[entry.Name] = credential(entry)
+		principals[credential(entry)] = entry.Name
+	}
+
+	return credentials, nil
+}
+
+// match returns the principal associated to a given credential, comparing all the configured ones in constant time.
+// If several principals share the credential, the first one in lexical order is returned, whatever the map order.
+func match(credentials map[string]string, credential string) (string, bool) {
+	var matched string
+
+	for principal, expected := range credentials {
+		if expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(credential)) == 1 &&
+			(matched == "" || principal < matched) {
+			matched = principal
+		}
+	}
+
+	return matched, matched != ""
+}
@@ -0,0 +1,207 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMCPAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := auth.NewDefaultMCPAuthenticator(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          auth:
+            bearer:
+              tokens:
+                - name: Alice
+                  token: alice-token
+                - name: BOB
+                  token: bob-token
+            api_key:
+              header: X-Custom-Key
+              keys:
+                - name: CI-Runner
+                  key: ci-key
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		principal string
+	}{
+		{"bearer token", "Authorization", "Bearer alice-token", "Alice"},
+		{"bearer token scheme case", "Authorization", "bearer bob-token", "BOB"},
+		{"api key", "X-Custom-Key", "ci-key", "CI-Runner"},
+		{"invalid bearer token", "Authorization", "Bearer invalid", ""},
+		{"api key as bearer token", "Authorization", "Bearer ci-key", ""},
+		{"invalid api key", "X-Custom-Key", "invalid", ""},
+		{"no credential", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			principal, err := authenticator.Authenticate(req)
+
+			if tt.principal == "" {
+				assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.principal, principal)
+		})
+	}
+}
+
+func TestDefaultMCPAuthenticatorInvalidPrincipals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name: "map of principals",
+			content: `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          auth:
+            bearer:
+              tokens:
+                alice: alice-token
+`,
+			err: "invalid MCP principals in modules.mcp.server.transport.sse.auth.bearer.tokens: expected a list of entries",
+		},
+		{
+			name: "missing credential",
+			content: `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          auth:
+            api_key:
+              keys:
+                - name: ci
+                  token: ci-key
+`,
+			err: "invalid MCP principal at modules.mcp.server.transport.sse.auth.api_key.keys[0]: name and credential are required",
+		},
+		{
+			name: "duplicate principal",
+			content: `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          auth:
+            bearer:
+              tokens:
+                - name: alice
+                  token: first-token
+                - name: alice
+                  token: second-token
+`,
+			err: `duplicate MCP principal "alice" in modules.mcp.server.transport.sse.auth.bearer.tokens`,
+		},
+		{
+			name: "duplicate credential",
+			content: `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          auth:
+            api_key:
+              keys:
+                - name: ci
+                  key: shared-key
+                - name: cd
+                  key: shared-key
+`,
+			err: `duplicate MCP credential in modules.mcp.server.transport.sse.auth.api_key.keys: shared by principals "ci" and "cd"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := auth.NewDefaultMCPAuthenticator(mcptest.NewConfig(t, tt.content))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := auth.Middleware(
+		auth.NewStaticBearerTokenAuthenticator(map[string]string{"Alice": "alice-token"}),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(yokaimcpservercontext.CtxPrincipal(r.Context())))
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sse", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer realm="mcp"`, rec.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	req.Header.Set("Authorization", "Bearer alice-token")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Alice", rec.Body.String())
+}
+
+func TestStaticBearerTokenAuthenticatorSharedToken(t *testing.T) {
+	t.Parallel()
+
+	authenticator := auth.NewStaticBearerTokenAuthenticator(map[string]string{
+		"charlie": "shared-token",
+		"alice":   "shared-token",
+		"bob":     "shared-token",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	req.Header.Set("Authorization", "Bearer shared-token")
+
+	for range 20 {
+		principal, err := authenticator.Authenticate(req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal)
+	}
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+var _ MCPAuthenticator = (*StaticBearerTokenAuthenticator)(nil)
+
+// StaticBearerTokenAuthenticator authenticates requests from their Authorization bearer token, against static tokens
+// indexed by principal.
+type StaticBearerTokenAuthenticator struct {
+	tokens map[string]string
+}
+
+// NewStaticBearerTokenAuthenticator returns a new StaticBearerTokenAuthenticator.
+func NewStaticBearerTokenAuthenticator(tokens map[string]string) *StaticBearerTokenAuthenticator {
+	return &StaticBearerTokenAuthenticator{
+		tokens: tokens,
+	}
+}
+
+func (a *StaticBearerTokenAuthenticator) Authenticate(r *http.Request) (string, error) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", ErrUnauthenticated
+	}
+
+	principal, ok := match(a.tokens, token)
+	if !ok {
+		return "", ErrUnauthenticated
+	}
+
+	return principal, nil
+}
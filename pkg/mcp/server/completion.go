@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MaxCompletionValues is the maximum number of completion values returned to the client, as per MCP specification.
+const MaxCompletionValues = 100
+
+var (
+	_ server.PromptCompletionProvider   = (*MCPServerRegistry)(nil)
+	_ server.ResourceCompletionProvider = (*MCPServerRegistry)(nil)
+)
+
+// MCPServerCompletionProvider is an optional interface that MCPServerPrompt and MCPServerResourceTemplate
+// implementations can provide, to offer completion of their arguments.
+type MCPServerCompletionProvider interface {
+	Complete(ctx context.Context, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error)
+}
+
+// CompletePromptArgument provides completions for a registered prompt argument.
+func (r *MCPServerRegistry) CompletePromptArgument(
+	ctx context.Context,
+	promptName string,
+	argument mcp.CompleteArgument,
+	context mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	r.mutex.RLock()
+	prompt, ok := r.prompts[promptName]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return emptyCompletion(), nil
+	}
+
+	provider, ok := prompt.(MCPServerCompletionProvider)
+	if !ok {
+		return emptyCompletion(), nil
+	}
+
+	completion, err := provider.Complete(ctx, argument, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return limitCompletion(completion), nil
+}
+
+// CompleteResourceArgument provides completions for a registered resource template argument.
+func (r *MCPServerRegistry) CompleteResourceArgument(
+	ctx context.Context,
+	uri string,
+	argument mcp.CompleteArgument,
+	context mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	var provider MCPServerCompletionProvider
+
+	r.mutex.RLock()
+	for _, resourceTemplate := range r.resourceTemplates {
+		if resourceTemplate.URI() == uri {
+			provider, _ = resourceTemplate.(MCPServerCompletionProvider)
+
+			break
+		}
+	}
+	r.mutex.RUnlock()
+
+	if provider == nil {
+		return emptyCompletion(), nil
+	}
+
+	completion, err := provider.Complete(ctx, argument, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return limitCompletion(completion), nil
+}
+
+// CompletionReference returns the prompt name or resource template URI referenced by a given mcp.CompleteRequest.
+func CompletionReference(request *mcp.CompleteRequest) string {
+	switch ref := request.Params.Ref.(type) {
+	case mcp.PromptReference:
+		return ref.Name
+	case mcp.ResourceReference:
+		return ref.URI
+	default:
+		return ""
+	}
+}
+
+func emptyCompletion() *mcp.Completion {
+	return &mcp.Completion{
+		Values: []string{},
+	}
+}
+
+func limitCompletion(completion *mcp.Completion) *mcp.Completion {
+	if completion == nil {
+		return emptyCompletion()
+	}
+
+	if len(completion.Values) > MaxCompletionValues {
+		if completion.Total == 0 {
+			completion.Total = len(completion.Values)
+		}
+
+		completion.Values = completion.Values[:MaxCompletionValues]
+		completion.HasMore = true
+	}
+
+	return completion
+}
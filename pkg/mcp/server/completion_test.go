@@ -0,0 +1,127 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCompletionPrompt struct {
+	testPrompt
+}
+
+func (p *testCompletionPrompt) Complete(
+	ctx context.Context,
+	argument mcp.CompleteArgument,
+	context mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	if argument.Value == "error" {
+		return nil, errors.New("completion error")
+	}
+
+	values := []string{}
+	for i := range 150 {
+		values = append(values, fmt.Sprintf("%s-%s-%d", argument.Name, argument.Value, i))
+	}
+
+	return &mcp.Completion{Values: values}, nil
+}
+
+type testCompletionResourceTemplate struct {
+	testResourceTemplate
+}
+
+func (r *testCompletionResourceTemplate) Complete(
+	ctx context.Context,
+	argument mcp.CompleteArgument,
+	context mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	return &mcp.Completion{Values: []string{strings.ToUpper(argument.Value)}}, nil
+}
+
+func newTestCompletionRegistry(t *testing.T) *yokaimcpserver.MCPServerRegistry {
+	t.Helper()
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		mcptest.NewConfig(t, allCapabilitiesConfig),
+		nil,
+		[]yokaimcpserver.MCPServerPrompt{
+			&testCompletionPrompt{testPrompt{name: "greet"}},
+			&testPrompt{name: "plain"},
+		},
+		nil,
+		[]yokaimcpserver.MCPServerResourceTemplate{
+			&testCompletionResourceTemplate{testResourceTemplate{name: "book", uri: "books://{id}"}},
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	return registry
+}
+
+func TestMCPServerRegistryCompletePromptArgument(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestCompletionRegistry(t)
+	ctx := context.Background()
+
+	completion, err := registry.CompletePromptArgument(ctx, "greet", mcp.CompleteArgument{Name: "name", Value: "jo"}, mcp.CompleteContext{})
+	require.NoError(t, err)
+	assert.Len(t, completion.Values, yokaimcpserver.MaxCompletionValues)
+	assert.Equal(t, "name-jo-0", completion.Values[0])
+	assert.Equal(t, 150, completion.Total)
+	assert.True(t, completion.HasMore)
+
+	completion, err = registry.CompletePromptArgument(ctx, "plain", mcp.CompleteArgument{Name: "name"}, mcp.CompleteContext{})
+	require.NoError(t, err)
+	assert.Empty(t, completion.Values)
+
+	completion, err = registry.CompletePromptArgument(ctx, "unknown", mcp.CompleteArgument{Name: "name"}, mcp.CompleteContext{})
+	require.NoError(t, err)
+	assert.Empty(t, completion.Values)
+
+	_, err = registry.CompletePromptArgument(ctx, "greet", mcp.CompleteArgument{Name: "name", Value: "error"}, mcp.CompleteContext{})
+	assert.ErrorContains(t, err, "completion error")
+}
+
+func TestMCPServerRegistryCompleteResourceArgument(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestCompletionRegistry(t)
+	ctx := context.Background()
+
+	completion, err := registry.CompleteResourceArgument(ctx, "books://{id}", mcp.CompleteArgument{Name: "id", Value: "ab"}, mcp.CompleteContext{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AB"}, completion.Values)
+	assert.False(t, completion.HasMore)
+
+	completion, err = registry.CompleteResourceArgument(ctx, "authors://{id}", mcp.CompleteArgument{Name: "id"}, mcp.CompleteContext{})
+	require.NoError(t, err)
+	assert.Empty(t, completion.Values)
+}
+
+func TestCompletionReference(t *testing.T) {
+	t.Parallel()
+
+	request := &mcp.CompleteRequest{}
+
+	request.Params.Ref = mcp.PromptReference{Type: "ref/prompt", Name: "greet"}
+	assert.Equal(t, "greet", yokaimcpserver.CompletionReference(request))
+
+	request.Params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: "books://{id}"}
+	assert.Equal(t, "books://{id}", yokaimcpserver.CompletionReference(request))
+
+	request.Params.Ref = nil
+	assert.Equal(t, "", yokaimcpserver.CompletionReference(request))
+}
@@ -11,6 +11,9 @@ type CtxRequestIdKey struct{}
 type CtxSessionIdKey struct{}
 type CtxRootSpanKey struct{}
 type CtxStartTimeKey struct{}
+type CtxPrincipalKey struct{}
+type CtxMessageKey struct{}
+type CtxClientCertificateSubjectKey struct{}
 
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, CtxRequestIdKey{}, requestID)
@@ -48,6 +51,12 @@ func CtxRootSpan(ctx context.Context) trace.Span {
 	return trace.SpanFromContext(ctx)
 }
 
+func CtxHasRootSpan(ctx context.Context) bool {
+	_, ok := ctx.Value(CtxRootSpanKey{}).(trace.Span)
+
+	return ok
+}
+
 func WithStartTime(ctx context.Context, t time.Time) context.Context {
 	return context.WithValue(ctx, CtxStartTimeKey{}, t)
 }
@@ -59,3 +68,43 @@ func CtxStartTime(ctx context.Context) time.Time {
 
 	return time.Now()
 }
+
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, CtxPrincipalKey{}, principal)
+}
+
+func CtxPrincipal(ctx context.Context) string {
+	if principal, ok := ctx.Value(CtxPrincipalKey{}).(string); ok {
+		return principal
+	}
+
+	return ""
+}
+
+func CtxHasPrincipal(ctx context.Context) bool {
+	return CtxPrincipal(ctx) != ""
+}
+
+func WithMessage(ctx context.Context, message []byte) context.Context {
+	return context.WithValue(ctx, CtxMessageKey{}, message)
+}
+
+func CtxMessage(ctx context.Context) []byte {
+	if message, ok := ctx.Value(CtxMessageKey{}).([]byte); ok {
+		return message
+	}
+
+	return nil
+}
+
+func WithClientCertificateSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, CtxClientCertificateSubjectKey{}, subject)
+}
+
+func CtxClientCertificateSubject(ctx context.Context) string {
+	if subject, ok := ctx.Value(CtxClientCertificateSubjectKey{}).(string); ok {
+		return subject
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceContextPropagator propagates the W3C trace context (traceparent, tracestate) and baggage.
+var TraceContextPropagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// WithHeaderTraceContext returns a context carrying the W3C trace context and baggage found in given HTTP headers.
+func WithHeaderTraceContext(ctx context.Context, header http.Header) context.Context {
+	return TraceContextPropagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// WithMetaTraceContext returns a context carrying the W3C trace context and baggage found in a given MCP request _meta.
+func WithMetaTraceContext(ctx context.Context, meta map[string]any) context.Context {
+	carrier := propagation.MapCarrier{}
+
+	for _, field := range TraceContextPropagator.Fields() {
+		if value, ok := meta[field].(string); ok && value != "" {
+			carrier[field] = value
+		}
+	}
+
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	return TraceContextPropagator.Extract(ctx, carrier)
+}
+
+// WithMessageTraceContext returns a context carrying the W3C trace context and baggage found in the params._meta of a
+// given raw MCP JSON-RPC message.
+func WithMessageTraceContext(ctx context.Context, message []byte) context.Context {
+	var msg struct {
+		Params struct {
+			Meta map[string]any `json:"_meta"`
+		} `json:"params"`
+	}
+
+	if err := json.Unmarshal(message, &msg); err != nil || len(msg.Params.Meta) == 0 {
+		return ctx
+	}
+
+	return WithMetaTraceContext(ctx, msg.Params.Meta)
+}
@@ -0,0 +1,59 @@
+package context_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testTraceParent = "00-" + testTraceID + "-00f067aa0ba902b7-01"
+)
+
+func TestWithHeaderTraceContext(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("traceparent", testTraceParent)
+	header.Set("baggage", "tenant=acme")
+
+	ctx := yokaimcpservercontext.WithHeaderTraceContext(context.Background(), header)
+
+	spanContext := oteltrace.SpanContextFromContext(ctx)
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, testTraceID, spanContext.TraceID().String())
+	assert.Equal(t, "acme", baggage.FromContext(ctx).Member("tenant").Value())
+}
+
+func TestWithMetaTraceContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := yokaimcpservercontext.WithMetaTraceContext(context.Background(), map[string]any{
+		"traceparent":   testTraceParent,
+		"progressToken": 1,
+	})
+	assert.Equal(t, testTraceID, oteltrace.SpanContextFromContext(ctx).TraceID().String())
+
+	ctx = context.Background()
+	assert.Equal(t, ctx, yokaimcpservercontext.WithMetaTraceContext(ctx, nil))
+	assert.Equal(t, ctx, yokaimcpservercontext.WithMetaTraceContext(ctx, map[string]any{"traceparent": 42}))
+}
+
+func TestWithMessageTraceContext(t *testing.T) {
+	t.Parallel()
+
+	message := []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"greet","_meta":{"traceparent":"` + testTraceParent + `"}}}`)
+
+	ctx := yokaimcpservercontext.WithMessageTraceContext(context.Background(), message)
+	assert.Equal(t, testTraceID, oteltrace.SpanContextFromContext(ctx).TraceID().String())
+
+	ctx = context.Background()
+	assert.Equal(t, ctx, yokaimcpservercontext.WithMessageTraceContext(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)))
+	assert.Equal(t, ctx, yokaimcpservercontext.WithMessageTraceContext(ctx, []byte(`invalid`)))
+}
@@ -0,0 +1,67 @@
+package context
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultRootSpanTimeout is the default duration after which a root span not ended yet is force ended.
+const DefaultRootSpanTimeout = 5 * time.Minute
+
+// RootSpan is a MCP message root span ended only once: by the hooks when the request completes, or after a timeout
+// for messages never completing (cancelled or unanswered requests), so no span is left open.
+type RootSpan struct {
+	trace.Span
+	once  sync.Once
+	timer *time.Timer
+}
+
+// NewRootSpan returns a new RootSpan, wrapping a given span, force ended after a given timeout (0 to disable).
+func NewRootSpan(span trace.Span, timeout time.Duration) *RootSpan {
+	rootSpan := &RootSpan{
+		Span: span,
+	}
+
+	if timeout > 0 {
+		rootSpan.timer = time.AfterFunc(timeout, func() {
+			rootSpan.Span.SetAttributes(attribute.Bool("mcp.timeout", true))
+			rootSpan.Span.SetStatus(codes.Error, "MCP request not completed")
+			rootSpan.End()
+		})
+	}
+
+	return rootSpan
+}
+
+// End ends the root span, only once.
+func (s *RootSpan) End(options ...trace.SpanEndOption) {
+	s.once.Do(func() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+
+		s.Span.End(options...)
+	})
+}
+
+// MessageKind returns the method of a given raw MCP JSON-RPC message, and if it's a request (expecting a response),
+// as opposed to notifications and responses.
+func MessageKind(message []byte) (string, bool) {
+	var msg struct {
+		ID     any             `json:"id"`
+		Method string          `json:"method"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return "", false
+	}
+
+	return msg.Method, msg.ID != nil && msg.Method != "" && msg.Result == nil && msg.Error == nil
+}
@@ -0,0 +1,157 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/trace"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// TransportMessage describes a MCP message received by a transport, as identified by this transport.
+type TransportMessage struct {
+	Transport string
+	SessionID string
+	RequestID string
+	Method    string
+	IsRequest bool
+}
+
+// TransportContextHandler prepares the context of the MCP messages received by the transports creating their own
+// root spans: start time, session and request IDs, root span and contextual logger.
+type TransportContextHandler struct {
+	tracerProvider  oteltrace.TracerProvider
+	logger          *log.Logger
+	traceExclusions []string
+	spanTimeout     time.Duration
+}
+
+// NewTransportContextHandler returns a new TransportContextHandler, excluding from tracing the methods listed in
+// modules.mcp.server.trace.exclude, and force ending root spans after modules.mcp.server.trace.span_timeout.
+func NewTransportContextHandler(
+	config *config.Config,
+	tracerProvider oteltrace.TracerProvider,
+	logger *log.Logger,
+) *TransportContextHandler {
+	spanTimeout := DefaultRootSpanTimeout
+	if config.IsSet("modules.mcp.server.trace.span_timeout") {
+		spanTimeout = config.GetDuration("modules.mcp.server.trace.span_timeout")
+	}
+
+	return &TransportContextHandler{
+		tracerProvider:  tracerProvider,
+		logger:          logger,
+		traceExclusions: config.GetStringSlice("modules.mcp.server.trace.exclude"),
+		spanTimeout:     spanTimeout,
+	}
+}
+
+// Handle returns the context of a given message, with a given start time. The trace context extracted by the
+// transport from the message, if any, must already be in the provided context to be used as parent.
+func (h *TransportContextHandler) Handle(ctx context.Context, start time.Time, message TransportMessage) context.Context {
+	// start time propagation
+	ctx = WithStartTime(ctx, start)
+
+	// sessionId and requestId propagation
+	ctx = WithSessionID(ctx, message.SessionID)
+	ctx = WithRequestID(ctx, message.RequestID)
+
+	// principal and client certificate propagation: already stored in the context when authenticated
+	principal := CtxPrincipal(ctx)
+	clientCertSubject := CtxClientCertificateSubject(ctx)
+
+	// tracer propagation
+	ctx = trace.WithContext(ctx, h.tracerProvider)
+
+	spanOptions := []oteltrace.SpanStartOption{
+		oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+		oteltrace.WithAttributes(
+			attribute.String("system", "mcpserver"),
+			attribute.String("mcp.transport", message.Transport),
+			attribute.String("mcp.sessionID", message.SessionID),
+			attribute.String("mcp.requestID", message.RequestID),
+		),
+	}
+
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		spanOptions = append(spanOptions, oteltrace.WithNewRoot())
+	}
+
+	if principal != "" {
+		spanOptions = append(spanOptions, oteltrace.WithAttributes(attribute.String("enduser.id", principal)))
+	}
+
+	if clientCertSubject != "" {
+		spanOptions = append(spanOptions, oteltrace.WithAttributes(attribute.String("tls.client.subject", clientCertSubject)))
+	}
+
+	spanName := "MCP"
+	if message.Method != "" {
+		spanName = fmt.Sprintf("MCP %s", message.Method)
+		spanOptions = append(spanOptions, oteltrace.WithAttributes(attribute.String("mcp.method", message.Method)))
+	}
+
+	if !slices.Contains(h.traceExclusions, message.Method) {
+		var span oteltrace.Span
+		ctx, span = trace.CtxTracer(ctx).Start(ctx, spanName, spanOptions...)
+
+		if message.IsRequest {
+			// ended by the hooks once the request completes
+			ctx = WithRootSpan(ctx, NewRootSpan(span, h.spanTimeout))
+		} else {
+			// notifications and responses are never completed by the hooks: recorded as short spans
+			span.End()
+
+			ctx = WithRootSpan(ctx, span)
+		}
+	}
+
+	// logger propagation
+	loggerContext := h.logger.
+		With().
+		Str("system", "mcpserver").
+		Str("mcpTransport", message.Transport).
+		Str("mcpSessionID", message.SessionID).
+		Str("mcpRequestID", message.RequestID)
+
+	if principal != "" {
+		loggerContext = loggerContext.Str("mcpPrincipal", principal)
+	}
+
+	if clientCertSubject != "" {
+		loggerContext = loggerContext.Str("mcpClientCertSubject", clientCertSubject)
+	}
+
+	if spanContext := oteltrace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		loggerContext = loggerContext.
+			Str("traceID", spanContext.TraceID().String()).
+			Str("spanID", spanContext.SpanID().String())
+	}
+
+	logger := loggerContext.Logger()
+
+	return logger.WithContext(ctx)
+}
+
+// ReadMessage reads the body of a given HTTP request (the MCP message), limited to maxMessageSize bytes if positive,
+// and replaces it by an in memory copy so that it can be read again. The returned error wraps a *http.MaxBytesError
+// if the body exceeds the limit.
+func ReadMessage(w http.ResponseWriter, r *http.Request, maxMessageSize int64) ([]byte, error) {
+	body := r.Body
+	if maxMessageSize > 0 {
+		body = http.MaxBytesReader(w, body, maxMessageSize)
+	}
+
+	message, err := io.ReadAll(body)
+	r.Body = io.NopCloser(bytes.NewReader(message))
+
+	return message, err
+}
@@ -0,0 +1,188 @@
+package context_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace"
+	"github.com/ankorstore/yokai/trace/tracetest"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type testTransportContextHandler struct {
+	handler        *yokaimcpservercontext.TransportContextHandler
+	tracerProvider oteltrace.TracerProvider
+	logs           logtest.TestLogBuffer
+	spans          tracetest.TestTraceExporter
+}
+
+func newTestTransportContextHandler(t *testing.T, content string) *testTransportContextHandler {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600))
+
+	cfg, err := config.NewDefaultConfigFactory().Create(config.WithFilePaths(dir))
+	require.NoError(t, err)
+
+	logs := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(log.WithOutputWriter(logs))
+	require.NoError(t, err)
+
+	spans := tracetest.NewDefaultTestTraceExporter()
+	tracerProvider, err := trace.NewDefaultTracerProviderFactory().Create(
+		trace.Global(false),
+		trace.WithSpanProcessor(trace.NewTestSpanProcessor(spans)),
+	)
+	require.NoError(t, err)
+
+	return &testTransportContextHandler{
+		handler:        yokaimcpservercontext.NewTransportContextHandler(cfg, tracerProvider, logger),
+		tracerProvider: tracerProvider,
+		logs:           logs,
+		spans:          spans,
+	}
+}
+
+func TestTransportContextHandlerRequest(t *testing.T) {
+	t.Parallel()
+
+	h := newTestTransportContextHandler(t, "app:\n  name: test\n")
+
+	start := time.Now().Add(-time.Second)
+
+	ctx := yokaimcpservercontext.WithPrincipal(context.Background(), "alice")
+	ctx = yokaimcpservercontext.WithClientCertificateSubject(ctx, "CN=alice")
+
+	ctx = h.handler.Handle(ctx, start, yokaimcpservercontext.TransportMessage{
+		Transport: "sse",
+		SessionID: "s1",
+		RequestID: "r1",
+		Method:    "tools/call",
+		IsRequest: true,
+	})
+
+	assert.Equal(t, start, yokaimcpservercontext.CtxStartTime(ctx))
+	assert.Equal(t, "s1", yokaimcpservercontext.CtxSessionID(ctx))
+	assert.Equal(t, "r1", yokaimcpservercontext.CtxRequestId(ctx))
+
+	// ended by the hooks once the request completes
+	rootSpan, ok := yokaimcpservercontext.CtxRootSpan(ctx).(*yokaimcpservercontext.RootSpan)
+	require.True(t, ok)
+	assert.False(t, h.spans.HasSpan("MCP tools/call"))
+
+	rootSpan.End()
+
+	tracetest.AssertHasTraceSpan(
+		t,
+		h.spans,
+		"MCP tools/call",
+		attribute.String("mcp.transport", "sse"),
+		attribute.String("mcp.sessionID", "s1"),
+		attribute.String("mcp.requestID", "r1"),
+		attribute.String("mcp.method", "tools/call"),
+		attribute.String("enduser.id", "alice"),
+		attribute.String("tls.client.subject", "CN=alice"),
+	)
+
+	span, err := h.spans.Span("MCP tools/call")
+	require.NoError(t, err)
+	assert.False(t, span.Parent.IsValid())
+
+	log.CtxLogger(ctx).Info().Msg("test")
+
+	logtest.AssertHasLogRecord(t, h.logs, map[string]any{
+		"system":               "mcpserver",
+		"mcpTransport":         "sse",
+		"mcpSessionID":         "s1",
+		"mcpRequestID":         "r1",
+		"mcpPrincipal":         "alice",
+		"mcpClientCertSubject": "CN=alice",
+		"traceID":              span.SpanContext.TraceID().String(),
+		"spanID":               span.SpanContext.SpanID().String(),
+		"message":              "test",
+	})
+}
+
+func TestTransportContextHandlerParentSpan(t *testing.T) {
+	t.Parallel()
+
+	h := newTestTransportContextHandler(t, "app:\n  name: test\n")
+
+	// remote parent, from the request headers
+	header := http.Header{}
+	header.Set("traceparent", testTraceParent)
+
+	ctx := h.handler.Handle(
+		yokaimcpservercontext.WithHeaderTraceContext(context.Background(), header),
+		time.Now(),
+		yokaimcpservercontext.TransportMessage{Transport: "streamable_http", Method: "ping", IsRequest: true},
+	)
+	yokaimcpservercontext.CtxRootSpan(ctx).End()
+
+	span, err := h.spans.Span("MCP ping")
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, span.SpanContext.TraceID().String())
+	assert.True(t, span.Parent.IsRemote())
+
+	h.spans.Reset()
+
+	// local parent, from the HTTP server the transport is mounted on
+	parentCtx, parentSpan := h.tracerProvider.Tracer("test").Start(context.Background(), "GET /mcp")
+
+	ctx = h.handler.Handle(
+		parentCtx,
+		time.Now(),
+		yokaimcpservercontext.TransportMessage{Transport: "streamable_http", Method: "ping", IsRequest: true},
+	)
+	yokaimcpservercontext.CtxRootSpan(ctx).End()
+	parentSpan.End()
+
+	span, err = h.spans.Span("MCP ping")
+	require.NoError(t, err)
+	assert.Equal(t, parentSpan.SpanContext().TraceID(), span.SpanContext.TraceID())
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), span.Parent.SpanID())
+}
+
+func TestTransportContextHandlerNotificationAndExclusions(t *testing.T) {
+	t.Parallel()
+
+	h := newTestTransportContextHandler(t, `
+modules:
+  mcp:
+    server:
+      trace:
+        exclude:
+          - "ping"
+`)
+
+	// notifications are never completed by the hooks: recorded as short spans
+	ctx := h.handler.Handle(context.Background(), time.Now(), yokaimcpservercontext.TransportMessage{
+		Transport: "websocket",
+		Method:    "notifications/initialized",
+	})
+
+	assert.True(t, yokaimcpservercontext.CtxHasRootSpan(ctx))
+	assert.True(t, h.spans.HasSpan("MCP notifications/initialized", attribute.String("mcp.transport", "websocket")))
+
+	// excluded methods are not traced
+	ctx = h.handler.Handle(context.Background(), time.Now(), yokaimcpservercontext.TransportMessage{
+		Transport: "websocket",
+		Method:    "ping",
+		IsRequest: true,
+	})
+
+	assert.False(t, yokaimcpservercontext.CtxHasRootSpan(ctx))
+	assert.False(t, h.spans.HasSpan("MCP ping"))
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Exemplar returns the exemplar labels (trace_id and span_id) of a given span, or nil if the span is not sampled.
+func Exemplar(span oteltrace.Span) prometheus.Labels {
+	if span == nil {
+		return nil
+	}
+
+	spanContext := span.SpanContext()
+	if !spanContext.IsSampled() {
+		return nil
+	}
+
+	return prometheus.Labels{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
+
+// ObserveWithExemplar observes a value on a given prometheus.Observer, with exemplar labels if provided and supported.
+func ObserveWithExemplar(observer prometheus.Observer, value float64, exemplar prometheus.Labels) {
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		exemplarObserver.ObserveWithExemplar(value, exemplar)
+
+		return
+	}
+
+	observer.Observe(value)
+}
+
+// IncWithExemplar increments a given prometheus.Counter, with exemplar labels if provided and supported.
+func IncWithExemplar(counter prometheus.Counter, exemplar prometheus.Labels) {
+	if exemplarAdder, ok := counter.(prometheus.ExemplarAdder); ok && exemplar != nil {
+		exemplarAdder.AddWithExemplar(1, exemplar)
+
+		return
+	}
+
+	counter.Inc()
+}
@@ -0,0 +1,120 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankorstore/yokai/log/logtest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestExemplar(t *testing.T) {
+	t.Parallel()
+
+	traceID := oteltrace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	spanID := oteltrace.SpanID{1, 2, 3, 4, 5, 6, 7, 8}
+
+	sampled := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+	notSampled := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	})
+
+	assert.Nil(t, yokaimcpserver.Exemplar(nil))
+	assert.Nil(t, yokaimcpserver.Exemplar(oteltrace.SpanFromContext(oteltrace.ContextWithSpanContext(context.Background(), notSampled))))
+	assert.Equal(
+		t,
+		prometheus.Labels{"trace_id": traceID.String(), "span_id": spanID.String()},
+		yokaimcpserver.Exemplar(oteltrace.SpanFromContext(oteltrace.ContextWithSpanContext(context.Background(), sampled))),
+	)
+}
+
+func TestWithExemplar(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total"}, []string{"kind"})
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_seconds"}, []string{"kind"})
+	registry.MustRegister(counter, histogram)
+
+	exemplar := prometheus.Labels{"trace_id": "trace", "span_id": "span"}
+
+	yokaimcpserver.IncWithExemplar(counter.WithLabelValues("with"), exemplar)
+	yokaimcpserver.IncWithExemplar(counter.WithLabelValues("without"), nil)
+	yokaimcpserver.ObserveWithExemplar(histogram.WithLabelValues("with"), 0.1, exemplar)
+	yokaimcpserver.ObserveWithExemplar(histogram.WithLabelValues("without"), 0.1, nil)
+
+	with := gatherMetric(t, registry, "test_total", map[string]string{"kind": "with"})
+	require.NotNil(t, with)
+	assert.Equal(t, float64(1), with.GetCounter().GetValue())
+	assert.Len(t, with.GetCounter().GetExemplar().GetLabel(), 2)
+
+	without := gatherMetric(t, registry, "test_total", map[string]string{"kind": "without"})
+	require.NotNil(t, without)
+	assert.Equal(t, float64(1), without.GetCounter().GetValue())
+	assert.Nil(t, without.GetCounter().GetExemplar())
+
+	withHistogram := gatherMetric(t, registry, "test_seconds", map[string]string{"kind": "with"}).GetHistogram()
+	assert.Equal(t, uint64(1), withHistogram.GetSampleCount())
+	assert.True(t, hasBucketExemplar(withHistogram.GetBucket()))
+
+	withoutHistogram := gatherMetric(t, registry, "test_seconds", map[string]string{"kind": "without"}).GetHistogram()
+	assert.Equal(t, uint64(1), withoutHistogram.GetSampleCount())
+	assert.False(t, hasBucketExemplar(withoutHistogram.GetBucket()))
+}
+
+func TestHooksExemplarsAndTraceLogFields(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, metricsConfig, newTestTool("book-list"))
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "book-list"})
+
+	span, err := s.spans.Span("MCP tools/call book-list")
+	require.NoError(t, err)
+
+	traceID := span.SpanContext.TraceID().String()
+	spanID := span.SpanContext.SpanID().String()
+
+	labels := map[string]string{"method": "tools/call", "target": "book-list"}
+
+	counter := gatherMetric(t, s.prometheus, "mcp_server_requests_total", labels)
+	require.NotNil(t, counter)
+
+	exemplar := map[string]string{}
+	for _, label := range counter.GetCounter().GetExemplar().GetLabel() {
+		exemplar[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, map[string]string{"trace_id": traceID, "span_id": spanID}, exemplar)
+
+	assert.True(t, hasBucketExemplar(histogram(t, s.prometheus, "mcp_server_requests_duration_seconds", labels).GetBucket()))
+
+	logtest.AssertHasLogRecord(t, s.logs, map[string]any{
+		"mcpMethod": "tools/call",
+		"traceID":   traceID,
+		"spanID":    spanID,
+		"message":   "MCP request success",
+	})
+}
+
+func hasBucketExemplar(buckets []*dto.Bucket) bool {
+	for _, bucket := range buckets {
+		if bucket.GetExemplar() != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -42,6 +42,18 @@ func (f *DefaultMCPServerFactory) Create(options ...server.ServerOption) *server
 		server.WithRecovery(),
 	}
 
+	if f.config.GetBool("modules.mcp.server.capabilities.tools") {
+		srvOptions = append(srvOptions, server.WithToolCapabilities(true))
+	}
+
+	if f.config.GetBool("modules.mcp.server.capabilities.prompts") {
+		srvOptions = append(srvOptions, server.WithPromptCapabilities(true))
+	}
+
+	if f.config.GetBool("modules.mcp.server.capabilities.resources") {
+		srvOptions = append(srvOptions, server.WithResourceCapabilities(false, true))
+	}
+
 	instructions := f.config.GetString("modules.mcp.server.instructions")
 	if instructions != "" {
 		srvOptions = append(srvOptions, server.WithInstructions(instructions))
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/ankorstore/yokai/config"
+)
+
+// MCPServerRegistrationFilterKeys are the config keys of the registration filters, under modules.mcp.server.
+var MCPServerRegistrationFilterKeys = []string{"tools", "prompts", "resources", "resource_templates"}
+
+// MCPServerRegistrationFilter filters MCP registrations by name, using include and exclude glob patterns.
+type MCPServerRegistrationFilter struct {
+	Key     string
+	Include []string
+	Exclude []string
+}
+
+// NewMCPServerRegistrationFilter returns a new MCPServerRegistrationFilter, configured from the provided config key.
+//
+// For example, for the key "tools", the patterns are read from modules.mcp.server.tools.include and
+// modules.mcp.server.tools.exclude.
+func NewMCPServerRegistrationFilter(config *config.Config, key string) *MCPServerRegistrationFilter {
+	return &MCPServerRegistrationFilter{
+		Key:     key,
+		Include: config.GetStringSlice(fmt.Sprintf("modules.mcp.server.%s.include", key)),
+		Exclude: config.GetStringSlice(fmt.Sprintf("modules.mcp.server.%s.exclude", key)),
+	}
+}
+
+// Validate returns an error wrapping path.ErrBadPattern if any include or exclude pattern is malformed, since a
+// malformed pattern would otherwise silently never match.
+func (f *MCPServerRegistrationFilter) Validate() error {
+	if err := validatePatterns(f.Key, "include", f.Include); err != nil {
+		return err
+	}
+
+	return validatePatterns(f.Key, "exclude", f.Exclude)
+}
+
+// Allow returns true if the provided name is allowed by the filter, or false with the reason why it was filtered out.
+//
+// A name is allowed if it matches at least one include pattern (or if there are no include patterns), and does not
+// match any exclude pattern.
+func (f *MCPServerRegistrationFilter) Allow(name string) (bool, string) {
+	if len(f.Include) > 0 {
+		included := false
+
+		for _, pattern := range f.Include {
+			if Match(pattern, name) {
+				included = true
+
+				break
+			}
+		}
+
+		if !included {
+			return false, fmt.Sprintf("not matching any include pattern %v", f.Include)
+		}
+	}
+
+	for _, pattern := range f.Exclude {
+		if Match(pattern, name) {
+			return false, fmt.Sprintf("matching exclude pattern %q", pattern)
+		}
+	}
+
+	return true, ""
+}
+
+// Match returns true if a given name matches a given glob pattern. Malformed patterns, rejected by Validate, never
+// match.
+func Match(pattern string, name string) bool {
+	matched, err := path.Match(pattern, name)
+
+	return err == nil && matched
+}
+
+func validatePatterns(key string, kind string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid MCP %s %s pattern %q: %w", key, kind, pattern, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package server_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPServerRegistrationFilterAllow(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      tools:
+        include:
+          - "book-*"
+          - "author-*"
+        exclude:
+          - "*-delete"
+`)
+
+	filter := yokaimcpserver.NewMCPServerRegistrationFilter(cfg, "tools")
+
+	allowed, reason := filter.Allow("book-list")
+	assert.True(t, allowed)
+	assert.Empty(t, reason)
+
+	allowed, reason = filter.Allow("author-get")
+	assert.True(t, allowed)
+	assert.Empty(t, reason)
+
+	allowed, reason = filter.Allow("weather")
+	assert.False(t, allowed)
+	assert.Equal(t, "not matching any include pattern [book-* author-*]", reason)
+
+	allowed, reason = filter.Allow("book-delete")
+	assert.False(t, allowed)
+	assert.Equal(t, `matching exclude pattern "*-delete"`, reason)
+}
+
+func TestMCPServerRegistrationFilterAllowWithoutPatterns(t *testing.T) {
+	t.Parallel()
+
+	filter := yokaimcpserver.NewMCPServerRegistrationFilter(mcptest.NewConfig(t, "app:\n  name: test\n"), "tools")
+
+	allowed, reason := filter.Allow("anything")
+	assert.True(t, allowed)
+	assert.Empty(t, reason)
+}
+
+func TestMCPServerRegistrationFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      tools:
+        include:
+          - "book-*"
+        exclude:
+          - "delete-[book"
+`)
+
+	err := yokaimcpserver.NewMCPServerRegistrationFilter(cfg, "tools").Validate()
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+	assert.Contains(t, err.Error(), `invalid MCP tools exclude pattern "delete-[book"`)
+
+	assert.NoError(t, yokaimcpserver.NewMCPServerRegistrationFilter(cfg, "prompts").Validate())
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, yokaimcpserver.Match("*", "book-list"))
+	assert.True(t, yokaimcpserver.Match("book-?", "book-1"))
+	assert.False(t, yokaimcpserver.Match("book-*", "author-list"))
+	assert.False(t, yokaimcpserver.Match("[", "["))
+}
+
+func TestMCPServerRegistryFiltering(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+        prompts: false
+        resources: true
+      tools:
+        exclude:
+          - "*-delete"
+      resource_templates:
+        include:
+          - "book"
+`)
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		cfg,
+		[]yokaimcpserver.MCPServerTool{newTestTool("book-list"), newTestTool("book-delete")},
+		[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}},
+		[]yokaimcpserver.MCPServerResource{&testResource{name: "weather", uri: "weather://current"}},
+		[]yokaimcpserver.MCPServerResourceTemplate{
+			&testResourceTemplate{name: "book", uri: "books://{id}"},
+			&testResourceTemplate{name: "author", uri: "authors://{id}"},
+		},
+		nil,
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	info := registry.Info()
+
+	assert.Contains(t, info.Registrations.Tools, "book-list")
+	assert.Contains(t, info.Registrations.Resources, "weather")
+	assert.Contains(t, info.Registrations.ResourceTemplates, "book")
+	assert.Empty(t, info.Registrations.Prompts)
+
+	assert.Equal(t, `matching exclude pattern "*-delete"`, info.Filtered.Tools["book-delete"])
+	assert.Equal(t, "prompts capability disabled", info.Filtered.Prompts["greet"])
+	assert.Equal(t, "not matching any include pattern [book]", info.Filtered.ResourceTemplates["author"])
+}
+
+func TestMCPServerRegistryInvalidFilterPattern(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      resource_templates:
+        include:
+          - "book[-*"
+`)
+
+	_, err := yokaimcpserver.NewMCPServerRegistry(
+		cfg,
+		[]yokaimcpserver.MCPServerTool{newTestTool("book-list")},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+	assert.Contains(t, err.Error(), `invalid MCP resource_templates include pattern "book[-*"`)
+}
@@ -8,25 +8,33 @@ import (
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
 	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/stdio"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/streamablehttp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/websocket"
 )
 
 // MCPServerProbe is a probe compatible with the healthcheck module.
 type MCPServerProbe struct {
-	config      *config.Config
-	sseServer   *sse.MCPSSEServer
-	stdioServer *stdio.MCPStdioServer
+	config               *config.Config
+	sseServer            *sse.MCPSSEServer
+	streamableHTTPServer *streamablehttp.MCPStreamableHTTPServer
+	webSocketServer      *websocket.MCPWebSocketServer
+	stdioServer          *stdio.MCPStdioServer
 }
 
 // NewMCPServerProbe returns a new MCPServerProbe.
 func NewMCPServerProbe(
 	config *config.Config,
 	sseServer *sse.MCPSSEServer,
+	streamableHTTPServer *streamablehttp.MCPStreamableHTTPServer,
+	webSocketServer *websocket.MCPWebSocketServer,
 	stdioServer *stdio.MCPStdioServer,
 ) *MCPServerProbe {
 	return &MCPServerProbe{
-		config:      config,
-		sseServer:   sseServer,
-		stdioServer: stdioServer,
+		config:               config,
+		sseServer:            sseServer,
+		streamableHTTPServer: streamableHTTPServer,
+		webSocketServer:      webSocketServer,
+		stdioServer:          stdioServer,
 	}
 }
 
@@ -41,7 +49,9 @@ func (p *MCPServerProbe) Check(context.Context) *healthcheck.CheckerProbeResult
 	var messages []string
 
 	if p.config.GetBool("modules.mcp.server.transport.sse.expose") {
-		if p.sseServer.Running() {
+		if p.sseServer.Mounted() {
+			messages = append(messages, "MCP SSE server is mounted on the HTTP server")
+		} else if p.sseServer.Running() {
 			messages = append(messages, "MCP SSE server is running")
 		} else {
 			success = false
@@ -49,6 +59,24 @@ func (p *MCPServerProbe) Check(context.Context) *healthcheck.CheckerProbeResult
 		}
 	}
 
+	if p.config.GetBool("modules.mcp.server.transport.streamable_http.expose") {
+		if p.streamableHTTPServer.Running() {
+			messages = append(messages, "MCP Streamable HTTP server is running")
+		} else {
+			success = false
+			messages = append(messages, "MCP Streamable HTTP server is not running")
+		}
+	}
+
+	if p.config.GetBool("modules.mcp.server.transport.websocket.expose") {
+		if p.webSocketServer.Running() {
+			messages = append(messages, "MCP WebSocket server is running")
+		} else {
+			success = false
+			messages = append(messages, "MCP WebSocket server is not running")
+		}
+	}
+
 	if p.config.GetBool("modules.mcp.server.transport.stdio.expose") {
 		if p.stdioServer.Running() {
 			messages = append(messages, "MCP Stdio server is running")
@@ -0,0 +1,338 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace"
+	"github.com/ankorstore/yokai/trace/tracetest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const allCapabilitiesConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+        prompts: true
+        resources: true
+`
+
+type testTool struct {
+	name    string
+	options []mcp.ToolOption
+	handler server.ToolHandlerFunc
+}
+
+func newTestTool(name string, options ...mcp.ToolOption) *testTool {
+	return &testTool{
+		name:    name,
+		options: options,
+		handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name), nil
+		},
+	}
+}
+
+func (t *testTool) Name() string {
+	return t.name
+}
+
+func (t *testTool) Options() []mcp.ToolOption {
+	return t.options
+}
+
+func (t *testTool) Handle() server.ToolHandlerFunc {
+	return t.handler
+}
+
+type testPrompt struct {
+	name string
+}
+
+func (p *testPrompt) Name() string {
+	return p.name
+}
+
+func (p *testPrompt) Options() []mcp.PromptOption {
+	return nil
+}
+
+func (p *testPrompt) Handle() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult(p.name, nil), nil
+	}
+}
+
+type testResource struct {
+	name string
+	uri  string
+}
+
+func (r *testResource) Name() string {
+	return r.name
+}
+
+func (r *testResource) URI() string {
+	return r.uri
+}
+
+func (r *testResource) Options() []mcp.ResourceOption {
+	return nil
+}
+
+func (r *testResource) Handle() server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: r.name}}, nil
+	}
+}
+
+type testResourceTemplate struct {
+	name string
+	uri  string
+}
+
+func (r *testResourceTemplate) Name() string {
+	return r.name
+}
+
+func (r *testResourceTemplate) URI() string {
+	return r.uri
+}
+
+func (r *testResourceTemplate) Options() []mcp.ResourceTemplateOption {
+	return nil
+}
+
+func (r *testResourceTemplate) Handle() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: r.name}}, nil
+	}
+}
+
+// handleMessage sends a given JSON-RPC request to a given MCP server, and returns its JSON response.
+func handleMessage(tb testing.TB, mcpServer *server.MCPServer, method string, params any) map[string]any {
+	tb.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(tb, err)
+
+	response, err := json.Marshal(mcpServer.HandleMessage(context.Background(), message))
+	require.NoError(tb, err)
+
+	var decoded map[string]any
+	require.NoError(tb, json.Unmarshal(response, &decoded))
+
+	return decoded
+}
+
+// callTool calls a given tool on a given MCP server, and returns the text of its first result content.
+func callTool(tb testing.TB, mcpServer *server.MCPServer, name string, arguments map[string]any) string {
+	tb.Helper()
+
+	response := handleMessage(tb, mcpServer, string(mcp.MethodToolsCall), map[string]any{
+		"name":      name,
+		"arguments": arguments,
+	})
+	require.Contains(tb, response, "result", "unexpected response: %v", response)
+
+	content := response["result"].(map[string]any)["content"].([]any)
+	require.NotEmpty(tb, content)
+
+	return content[0].(map[string]any)["text"].(string)
+}
+
+type testSession struct {
+	id            string
+	transport     string
+	initialized   atomic.Bool
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession(id string) *testSession {
+	return &testSession{
+		id:            id,
+		transport:     "test",
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+	}
+}
+
+func (s *testSession) Initialize() {
+	s.initialized.Store(true)
+}
+
+func (s *testSession) Initialized() bool {
+	return s.initialized.Load()
+}
+
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *testSession) SessionID() string {
+	return s.id
+}
+
+func (s *testSession) Transport() string {
+	return s.transport
+}
+
+// testHooksServer is a MCP server using the default hooks, with Prometheus metrics collected on a dedicated registry.
+type testHooksServer struct {
+	ctx        context.Context
+	hooks      *server.Hooks
+	mcpServer  *server.MCPServer
+	registry   *yokaimcpserver.MCPServerRegistry
+	prometheus *prometheus.Registry
+	logs       logtest.TestLogBuffer
+	spans      tracetest.TestTraceExporter
+}
+
+func newTestHooksServer(tb testing.TB, content string, tools ...yokaimcpserver.MCPServerTool) *testHooksServer {
+	tb.Helper()
+
+	cfg := mcptest.NewConfig(tb, content)
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(cfg, tools, nil, nil, nil, nil, nil, nil)
+	require.NoError(tb, err)
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(cfg)
+	require.NoError(tb, err)
+
+	promRegistry := prometheus.NewRegistry()
+
+	hooks := yokaimcpserver.NewDefaultMCPServerHooksProvider(
+		cfg,
+		redactor,
+		yokaimcpserver.NewMCPServerMetricTargetResolver(cfg, registry),
+		yokaimcpserver.NewPrometheusMCPServerMetrics(promRegistry, cfg),
+	).Provide()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks), server.WithToolCapabilities(true))
+	registry.Register(mcpServer)
+
+	logs := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(log.WithLevel(zerolog.DebugLevel), log.WithOutputWriter(logs))
+	require.NoError(tb, err)
+
+	spans := tracetest.NewDefaultTestTraceExporter()
+	tracerProvider, err := trace.NewDefaultTracerProviderFactory().Create(
+		trace.Global(false),
+		trace.WithSpanProcessor(trace.NewTestSpanProcessor(spans)),
+	)
+	require.NoError(tb, err)
+
+	return &testHooksServer{
+		ctx:        trace.WithContext(logger.WithContext(context.Background()), tracerProvider),
+		hooks:      hooks,
+		mcpServer:  mcpServer,
+		registry:   registry,
+		prometheus: promRegistry,
+		logs:       logs,
+		spans:      spans,
+	}
+}
+
+// connect registers a given session, initializes it for a given client name, and returns the session context.
+func (s *testHooksServer) connect(tb testing.TB, session *testSession, clientName string) context.Context {
+	tb.Helper()
+
+	require.NoError(tb, s.mcpServer.RegisterSession(s.ctx, session))
+
+	ctx := s.mcpServer.WithContext(s.ctx, session)
+
+	s.handle(tb, ctx, string(mcp.MethodInitialize), map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"clientInfo":      map[string]any{"name": clientName, "version": "1.0.0"},
+		"capabilities":    map[string]any{},
+	})
+
+	return ctx
+}
+
+// handle sends a given JSON-RPC request in a given session context, and returns its JSON response.
+func (s *testHooksServer) handle(tb testing.TB, ctx context.Context, method string, params any) map[string]any {
+	tb.Helper()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(tb, err)
+
+	response, err := json.Marshal(s.mcpServer.HandleMessage(ctx, message))
+	require.NoError(tb, err)
+
+	var decoded map[string]any
+	require.NoError(tb, json.Unmarshal(response, &decoded))
+
+	return decoded
+}
+
+const metricsConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+        prompts: true
+        resources: true
+      metrics:
+        collect:
+          enabled: true
+`
+
+// histogram returns the Prometheus histogram of a given name and labels, or nil if not found.
+func histogram(tb testing.TB, registry *prometheus.Registry, name string, labels map[string]string) *dto.Histogram {
+	tb.Helper()
+
+	return gatherMetric(tb, registry, name, labels).GetHistogram()
+}
+
+// gatherMetric returns the Prometheus metric of a given name and labels, or nil if not found.
+func gatherMetric(tb testing.TB, registry *prometheus.Registry, name string, labels map[string]string) *dto.Metric {
+	tb.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(tb, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+					matched++
+				}
+			}
+
+			if matched == len(labels) {
+				return metric
+			}
+		}
+	}
+
+	return nil
+}
@@ -2,20 +2,22 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ankorstore/yokai/config"
 	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/trace"
 	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	otelsdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 var _ MCPServerHooksProvider = (*DefaultMCPServerHooksProvider)(nil)
@@ -24,241 +26,557 @@ type MCPServerHooksProvider interface {
 	Provide() *server.Hooks
 }
 
-type DefaultMCPServerHooksProvider struct {
-	config           *config.Config
-	requestsCounter  *prometheus.CounterVec
-	requestsDuration *prometheus.HistogramVec
+// MCPServerHook contributes hooks on top of the ones provided by the MCPServerHooksProvider, and can add functions to
+// any of the hook points offered by server.Hooks (before / after any, per method, on session, on error, etc.).
+type MCPServerHook interface {
+	Register(hooks *server.Hooks)
 }
 
-func NewDefaultMCPServerHooksProvider(registry prometheus.Registerer, config *config.Config) *DefaultMCPServerHooksProvider {
-	namespace := Sanitize(config.GetString("modules.mcp.server.metrics.collect.namespace"))
-	subsystem := Sanitize(config.GetString("modules.mcp.server.metrics.collect.subsystem"))
+// ComposeHooks returns the hooks of a given MCPServerHooksProvider, with the hooks of the given MCPServerHook list
+// registered on top of them, in order.
+func ComposeHooks(provider MCPServerHooksProvider, contributors ...MCPServerHook) *server.Hooks {
+	hooks := provider.Provide()
+	if hooks == nil {
+		hooks = &server.Hooks{}
+	}
 
-	buckets := prometheus.DefBuckets
-	if bucketsConfig := config.GetString("modules.mcp.server.metrics.buckets"); bucketsConfig != "" {
-		for _, s := range Split(bucketsConfig) {
-			f, err := strconv.ParseFloat(s, 64)
-			if err == nil {
-				buckets = append(buckets, f)
-			}
-		}
+	for _, contributor := range contributors {
+		contributor.Register(hooks)
 	}
 
-	requestsCounter := prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: namespace,
-			Subsystem: subsystem,
-			Name:      "mcp_server_requests_total",
-			Help:      "Number of processed MCP requests",
-		},
-		[]string{
-			"method",
-			"target",
-			"status",
-		},
-	)
-
-	requestsDuration := prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Namespace: namespace,
-			Subsystem: subsystem,
-			Name:      "mcp_server_requests_duration_seconds",
-			Help:      "Time spent processing MCP requests",
-			Buckets:   buckets,
-		},
-		[]string{
-			"method",
-			"target",
-		},
-	)
-
-	registry.MustRegister(requestsCounter, requestsDuration)
+	return hooks
+}
+
+type DefaultMCPServerHooksProvider struct {
+	config   *config.Config
+	redactor *MCPServerRedactor
+	targets  *MCPServerMetricTargetResolver
+	metrics  MCPServerMetrics
+	sessions sync.Map
+	requests sync.Map
+	evicted  atomic.Int64
+}
 
+func NewDefaultMCPServerHooksProvider(
+	config *config.Config,
+	redactor *MCPServerRedactor,
+	targets *MCPServerMetricTargetResolver,
+	metrics MCPServerMetrics,
+) *DefaultMCPServerHooksProvider {
 	return &DefaultMCPServerHooksProvider{
-		config:           config,
-		requestsCounter:  requestsCounter,
-		requestsDuration: requestsDuration,
+		config:   config,
+		redactor: redactor,
+		targets:  targets,
+		metrics:  metrics,
 	}
 }
 
 func (p *DefaultMCPServerHooksProvider) Provide() *server.Hooks {
 	hooks := &server.Hooks{}
 
-	traceRequest := p.config.GetBool("modules.mcp.server.trace.request")
-	traceResponse := p.config.GetBool("modules.mcp.server.trace.response")
-	traceExclusions := p.config.GetStringSlice("modules.mcp.server.trace.exclude")
+	settings := NewMCPServerHooksSettings(p.config)
+
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		stats := NewMCPServerSessionStats(SessionTransport(session))
 
-	logRequest := p.config.GetBool("modules.mcp.server.log.request")
-	logResponse := p.config.GetBool("modules.mcp.server.log.response")
-	logExclusions := p.config.GetStringSlice("modules.mcp.server.log.exclude")
+		p.sessions.Store(session.SessionID(), stats)
 
-	metricsEnabled := p.config.GetBool("modules.mcp.server.metrics.collect.enabled")
+		log.CtxLogger(ctx).Info().
+			Str("mcpSessionID", session.SessionID()).
+			Str("mcpTransport", stats.Transport()).
+			Msg("MCP session registered")
 
-	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
-		log.CtxLogger(ctx).Info().Str("mcpSessionID", session.SessionID()).Msg("MCP session registered")
+		if settings.MetricsEnabled {
+			p.metrics.SessionRegistered(ctx, stats.Transport())
+		}
 	})
 
-	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
-		latency := time.Since(yokaimcpservercontext.CtxStartTime(ctx))
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		stats, ok := p.sessionStats(SessionID(ctx))
+		if !ok || !stats.Initialize(message.Params.ClientInfo.Name) {
+			return
+		}
 
-		mcpMethod := string(method)
+		if settings.MetricsEnabled {
+			p.metrics.SessionInitialized(ctx, stats.Transport(), settings.MetricClient(stats.ClientName()))
+		}
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		value, ok := p.sessions.LoadAndDelete(session.SessionID())
+		if !ok {
+			return
+		}
 
-		spanNameSuffix := mcpMethod
+		//nolint:forcetypeassert
+		stats := value.(*MCPServerSessionStats)
+
+		p.endRequests(session.SessionID())
+
+		duration := stats.Duration()
+
+		log.CtxLogger(ctx).Info().
+			Str("mcpSessionID", session.SessionID()).
+			Str("mcpTransport", stats.Transport()).
+			Str("mcpClient", stats.ClientName()).
+			Str("mcpSessionDuration", duration.String()).
+			Int("mcpSessionRequests", stats.Requests()).
+			Int("mcpSessionErrors", stats.Errors()).
+			Msg("MCP session unregistered")
+
+		if settings.MetricsEnabled {
+			p.metrics.SessionUnregistered(
+				ctx,
+				stats.Transport(),
+				settings.MetricClient(stats.ClientName()),
+				stats.Initialized(),
+				duration,
+			)
+		}
+	})
 
-		spanAttributes := []attribute.KeyValue{
-			attribute.String("mcp.latency", latency.String()),
-			attribute.String("mcp.method", mcpMethod),
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if yokaimcpservercontext.CtxHasRootSpan(ctx) {
+			return
 		}
 
-		logFields := map[string]interface{}{
-			"mcpLatency": latency.String(),
-			"mcpMethod":  mcpMethod,
+		// transports creating their context once per connection (like stdio) get a root span per request
+		request := &MCPServerRequest{
+			startTime: time.Now(),
 		}
 
-		metricTarget := ""
+		p.evictRequests(request.startTime, settings.TraceSpanTimeout)
 
-		jsonMessage, err := json.Marshal(message)
-		if err == nil {
-			if traceRequest {
-				spanAttributes = append(spanAttributes, attribute.String("mcp.request", string(jsonMessage)))
+		if settings.Trace(method) {
+			spanOptions := []oteltrace.SpanStartOption{
+				oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+				oteltrace.WithAttributes(
+					attribute.String("system", "mcpserver"),
+					attribute.String("mcp.method", string(method)),
+				),
 			}
 
-			if logRequest {
-				logFields["mcpRequest"] = string(jsonMessage)
+			// the trace context of the request _meta, if any, is the parent of the root span
+			spanCtx := withRequestTraceContext(ctx, message)
+			if !oteltrace.SpanContextFromContext(spanCtx).IsRemote() {
+				spanOptions = append(spanOptions, oteltrace.WithNewRoot())
 			}
+
+			_, span := trace.CtxTracer(ctx).Start(spanCtx, fmt.Sprintf("MCP %s", method), spanOptions...)
+
+			request.span = yokaimcpservercontext.NewRootSpan(span, settings.TraceSpanTimeout)
+		}
+
+		p.requests.Store(requestKey(ctx, id, message), request)
+	})
+
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		rootSpan, startTime := p.request(ctx, id, message)
+
+		latency := time.Since(startTime)
+
+		toolErrMessage, isToolErr := ToolResultError(result)
+		if isToolErr {
+			toolErrMessage = p.redactor.RedactString(toolErrMessage)
+		}
+
+		if stats, ok := p.sessionStats(SessionID(ctx)); ok {
+			stats.Record(isToolErr)
+		}
+
+		traced := settings.Trace(method)
+		logged := settings.Log(method)
+
+		if !traced && rootSpan != nil {
+			rootSpan.End()
 		}
 
-		jsonResult, err := json.Marshal(result)
-		if err == nil {
-			if traceResponse {
-				spanAttributes = append(spanAttributes, attribute.String("mcp.response", string(jsonResult)))
+		if !traced && !logged && !settings.MetricsEnabled && !settings.CheckResponseSize(method) {
+			return
+		}
+
+		mcpMethod := string(method)
+		target := describeRequest(method, message)
+
+		request := NewMCPServerPayload(p.redactor, message)
+		response := NewMCPServerPayload(p.redactor, result)
+
+		if traced && rootSpan != nil {
+			spanAttributes := append(
+				[]attribute.KeyValue{
+					attribute.String("mcp.latency", latency.String()),
+					attribute.String("mcp.method", mcpMethod),
+				},
+				target.spanAttributes...,
+			)
+
+			if settings.TraceRequest {
+				if jsonMessage, ok := request.String(settings.TraceMaxPayloadSize); ok {
+					spanAttributes = append(
+						spanAttributes,
+						attribute.String("mcp.request", jsonMessage),
+						attribute.Int("mcp.request.size", request.Size()),
+					)
+				}
+			}
+
+			if settings.TraceResponse {
+				if jsonResult, ok := response.String(settings.TraceMaxPayloadSize); ok {
+					spanAttributes = append(
+						spanAttributes,
+						attribute.String("mcp.response", jsonResult),
+						attribute.Int("mcp.response.size", response.Size()),
+					)
+				}
 			}
 
-			if logResponse {
-				logFields["mcpResponse"] = string(jsonResult)
+			rootSpan.SetName(fmt.Sprintf("MCP %s", target.spanNameSuffix))
+			if isToolErr {
+				spanAttributes = append(spanAttributes, attribute.String("mcp.error", toolErrMessage))
+				rootSpan.RecordError(errors.New(toolErrMessage))
+				rootSpan.SetStatus(codes.Error, toolErrMessage)
+			} else {
+				rootSpan.SetStatus(codes.Ok, "MCP request success")
 			}
+			rootSpan.SetAttributes(spanAttributes...)
+			rootSpan.End()
+		}
+
+		if settings.CheckResponseSize(method) && response.Size() > settings.ResponseSizeThreshold {
+			log.CtxLogger(ctx).Warn().
+				Str("mcpTool", target.name).
+				Int("mcpResponseSize", response.Size()).
+				Int("mcpResponseSizeThreshold", settings.ResponseSizeThreshold).
+				Msg("MCP tool result size exceeds threshold")
 		}
 
-		switch method {
-		case mcp.MethodResourcesRead:
-			if req, ok := message.(*mcp.ReadResourceRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.URI)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.resource", req.Params.URI))
-				logFields["mcpResourceURI"] = req.Params.URI
-				metricTarget = req.Params.URI
+		if logged {
+			logFields := target.logFields
+			logFields["mcpLatency"] = latency.String()
+			logFields["mcpMethod"] = mcpMethod
+			p.addTraceLogFields(ctx, rootSpan, logFields)
+
+			if settings.LogRequest {
+				if jsonMessage, ok := request.String(settings.LogMaxPayloadSize); ok {
+					logFields["mcpRequest"] = jsonMessage
+				}
 			}
-		case mcp.MethodPromptsGet:
-			if req, ok := message.(*mcp.GetPromptRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.Name)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.prompt", req.Params.Name))
-				logFields["mcpPrompt"] = req.Params.Name
-				metricTarget = req.Params.Name
+
+			if settings.LogResponse {
+				if jsonResult, ok := response.String(settings.LogMaxPayloadSize); ok {
+					logFields["mcpResponse"] = jsonResult
+				}
 			}
-		case mcp.MethodToolsCall:
-			if req, ok := message.(*mcp.CallToolRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.Name)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.tool", req.Params.Name))
-				logFields["mcpTool"] = req.Params.Name
-				metricTarget = req.Params.Name
+
+			if isToolErr {
+				logFields["mcpError"] = toolErrMessage
+				log.CtxLogger(ctx).Warn().Fields(logFields).Msg("MCP request tool error")
+			} else {
+				log.CtxLogger(ctx).Info().Fields(logFields).Msg("MCP request success")
 			}
 		}
 
-		if !Contains(traceExclusions, mcpMethod) {
-			if rwSpan, ok := yokaimcpservercontext.CtxRootSpan(ctx).(otelsdktrace.ReadWriteSpan); ok {
-				rwSpan.SetName(fmt.Sprintf("%s %s", rwSpan.Name(), spanNameSuffix))
-				rwSpan.SetStatus(codes.Ok, "MCP request success")
-				rwSpan.SetAttributes(spanAttributes...)
-				rwSpan.End()
+		if settings.MetricsEnabled {
+			status := "success"
+			if isToolErr {
+				status = "tool_error"
 			}
-		}
 
-		if !Contains(logExclusions, mcpMethod) {
-			log.CtxLogger(ctx).Info().Fields(logFields).Msg("MCP request success")
-		}
+			metric := MCPServerRequestMetric{
+				Method:      mcpMethod,
+				Target:      p.targets.Resolve(method, message),
+				Status:      status,
+				Latency:     latency,
+				HasResponse: true,
+			}
+
+			if settings.MetricsSizesEnabled {
+				metric.RequestSize = request.Size()
+				metric.ResponseSize = response.Size()
+				metric.HasSizes = true
+			}
 
-		if metricsEnabled {
-			p.requestsCounter.WithLabelValues(mcpMethod, metricTarget, "success").Inc()
-			p.requestsDuration.WithLabelValues(mcpMethod, metricTarget).Observe(latency.Seconds())
+			p.metrics.RequestCompleted(oteltrace.ContextWithSpan(ctx, requestSpan(ctx, rootSpan)), metric)
 		}
 	})
 
 	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
-		latency := time.Since(yokaimcpservercontext.CtxStartTime(ctx))
+		rootSpan, startTime := p.request(ctx, id, message)
 
-		mcpMethod := string(method)
+		latency := time.Since(startTime)
 
-		errMessage := fmt.Sprintf("%v", err)
+		if stats, ok := p.sessionStats(SessionID(ctx)); ok {
+			stats.Record(true)
+		}
 
-		spanNameSuffix := mcpMethod
+		traced := settings.Trace(method)
+		logged := settings.Log(method)
 
-		spanAttributes := []attribute.KeyValue{
-			attribute.String("mcp.latency", latency.String()),
-			attribute.String("mcp.method", mcpMethod),
-			attribute.String("mcp.error", errMessage),
+		if !traced && rootSpan != nil {
+			rootSpan.End()
 		}
 
-		logFields := map[string]interface{}{
-			"mcpLatency": latency.String(),
-			"mcpMethod":  mcpMethod,
-			"mcpError":   errMessage,
+		if !traced && !logged && !settings.MetricsEnabled {
+			return
 		}
 
-		metricTarget := ""
-
-		jsonMessage, err := json.Marshal(message)
-		if err == nil {
-			if traceRequest {
-				spanAttributes = append(spanAttributes, attribute.String("mcp.request", string(jsonMessage)))
+		mcpMethod := string(method)
+		errMessage := p.redactor.RedactString(fmt.Sprintf("%v", err))
+		target := describeRequest(method, message)
+
+		request := NewMCPServerPayload(p.redactor, message)
+
+		if traced && rootSpan != nil {
+			spanAttributes := append(
+				[]attribute.KeyValue{
+					attribute.String("mcp.latency", latency.String()),
+					attribute.String("mcp.method", mcpMethod),
+					attribute.String("mcp.error", errMessage),
+				},
+				target.spanAttributes...,
+			)
+
+			if settings.TraceRequest {
+				if jsonMessage, ok := request.String(settings.TraceMaxPayloadSize); ok {
+					spanAttributes = append(
+						spanAttributes,
+						attribute.String("mcp.request", jsonMessage),
+						attribute.Int("mcp.request.size", request.Size()),
+					)
+				}
 			}
 
-			if logRequest {
-				logFields["mcpRequest"] = string(jsonMessage)
+			rootSpan.SetName(fmt.Sprintf("MCP %s", target.spanNameSuffix))
+			rootSpan.RecordError(errors.New(errMessage))
+			rootSpan.SetStatus(codes.Error, errMessage)
+			rootSpan.SetAttributes(spanAttributes...)
+			rootSpan.End()
+		}
+
+		if logged {
+			logFields := target.logFields
+			logFields["mcpLatency"] = latency.String()
+			logFields["mcpMethod"] = mcpMethod
+			p.addTraceLogFields(ctx, rootSpan, logFields)
+			logFields["mcpError"] = errMessage
+
+			if settings.LogRequest {
+				if jsonMessage, ok := request.String(settings.LogMaxPayloadSize); ok {
+					logFields["mcpRequest"] = jsonMessage
+				}
 			}
+
+			log.CtxLogger(ctx).Error().Fields(logFields).Msg("MCP request error")
 		}
 
-		switch method {
-		case mcp.MethodResourcesRead:
-			if req, ok := message.(*mcp.ReadResourceRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.URI)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.resource", req.Params.URI))
-				logFields["mcpResourceURI"] = req.Params.URI
-				metricTarget = req.Params.URI
+		if settings.MetricsEnabled {
+			metric := MCPServerRequestMetric{
+				Method:  mcpMethod,
+				Target:  p.targets.Resolve(method, message),
+				Status:  "error",
+				Latency: latency,
 			}
-		case mcp.MethodPromptsGet:
-			if req, ok := message.(*mcp.GetPromptRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.Name)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.prompt", req.Params.Name))
-				logFields["mcpPrompt"] = req.Params.Name
-				metricTarget = req.Params.Name
+
+			if settings.MetricsSizesEnabled {
+				metric.RequestSize = request.Size()
+				metric.HasSizes = true
 			}
-		case mcp.MethodToolsCall:
-			if req, ok := message.(*mcp.CallToolRequest); ok {
-				spanNameSuffix = fmt.Sprintf("%s %s", spanNameSuffix, req.Params.Name)
-				spanAttributes = append(spanAttributes, attribute.String("mcp.tool", req.Params.Name))
-				logFields["mcpTool"] = req.Params.Name
-				metricTarget = req.Params.Name
+
+			p.metrics.RequestCompleted(oteltrace.ContextWithSpan(ctx, requestSpan(ctx, rootSpan)), metric)
+		}
+	})
+
+	return hooks
+}
+
+// MCPServerRequest holds the root span and start time of a MCP request, for transports creating their context once
+// per connection.
+type MCPServerRequest struct {
+	span      *yokaimcpservercontext.RootSpan
+	startTime time.Time
+}
+
+// request returns the root span (nil if not traced) and start time of a MCP request.
+func (p *DefaultMCPServerHooksProvider) request(ctx context.Context, id any, message any) (oteltrace.Span, time.Time) {
+	if value, ok := p.requests.LoadAndDelete(requestKey(ctx, id, message)); ok {
+		//nolint:forcetypeassert
+		request := value.(*MCPServerRequest)
+		if request.span == nil {
+			return nil, request.startTime
+		}
+
+		return request.span, request.startTime
+	}
+
+	if yokaimcpservercontext.CtxHasRootSpan(ctx) {
+		return yokaimcpservercontext.CtxRootSpan(ctx), yokaimcpservercontext.CtxStartTime(ctx)
+	}
+
+	return nil, yokaimcpservercontext.CtxStartTime(ctx)
+}
+
+// endRequests ends the root spans of the requests of a given session never completed.
+func (p *DefaultMCPServerHooksProvider) endRequests(sessionID string) {
+	prefix := sessionID + ":"
+
+	p.requests.Range(func(key, value any) bool {
+		//nolint:forcetypeassert
+		if strings.HasPrefix(key.(string), prefix) {
+			p.requests.Delete(key)
+
+			//nolint:forcetypeassert
+			if request := value.(*MCPServerRequest); request.span != nil {
+				request.span.End()
 			}
 		}
 
-		if !Contains(traceExclusions, mcpMethod) {
-			if rwSpan, ok := yokaimcpservercontext.CtxRootSpan(ctx).(otelsdktrace.ReadWriteSpan); ok {
-				rwSpan.SetName(fmt.Sprintf("%s %s", rwSpan.Name(), spanNameSuffix))
-				rwSpan.RecordError(err)
-				rwSpan.SetStatus(codes.Error, errMessage)
-				rwSpan.SetAttributes(spanAttributes...)
-				rwSpan.End()
+		return true
+	})
+}
+
+// evictRequests removes the requests never completed (cancelled or unanswered) after a given timeout, ending their
+// root span. To keep BeforeAny cheap, the requests are scanned at most once per timeout.
+func (p *DefaultMCPServerHooksProvider) evictRequests(now time.Time, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = yokaimcpservercontext.DefaultRootSpanTimeout
+	}
+
+	last := p.evicted.Load()
+	if now.UnixNano()-last < int64(timeout) || !p.evicted.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	p.requests.Range(func(key, value any) bool {
+		//nolint:forcetypeassert
+		if request := value.(*MCPServerRequest); now.Sub(request.startTime) >= timeout {
+			p.requests.Delete(key)
+
+			if request.span != nil {
+				request.span.End()
 			}
 		}
 
-		if !Contains(logExclusions, mcpMethod) {
-			log.CtxLogger(ctx).Error().Fields(logFields).Msg("MCP request error")
+		return true
+	})
+}
+
+// addTraceLogFields adds the trace and span IDs of root spans started by the hooks to given log fields, since the
+// contextual logger of transports creating their context once per connection cannot carry them.
+func (p *DefaultMCPServerHooksProvider) addTraceLogFields(ctx context.Context, rootSpan oteltrace.Span, logFields map[string]any) {
+	if rootSpan == nil || yokaimcpservercontext.CtxHasRootSpan(ctx) {
+		return
+	}
+
+	if spanContext := rootSpan.SpanContext(); spanContext.IsValid() {
+		logFields["traceID"] = spanContext.TraceID().String()
+		logFields["spanID"] = spanContext.SpanID().String()
+	}
+}
+
+// requestSpan returns the root span of a request if any, or the span found in a given context.
+func requestSpan(ctx context.Context, rootSpan oteltrace.Span) oteltrace.Span {
+	if rootSpan != nil {
+		return rootSpan
+	}
+
+	return oteltrace.SpanFromContext(ctx)
+}
+
+// requestKey returns the key of a MCP request: its session and JSON-RPC ID, or without session, the request message
+// itself, since the hooks receive the same message pointer from BeforeAny to OnSuccess or OnError.
+func requestKey(ctx context.Context, id any, message any) string {
+	if sessionID := SessionID(ctx); sessionID != "" {
+		return fmt.Sprintf("%s:%v", sessionID, id)
+	}
+
+	return fmt.Sprintf(":%p", message)
+}
+
+func (p *DefaultMCPServerHooksProvider) sessionStats(sessionID string) (*MCPServerSessionStats, bool) {
+	if sessionID == "" {
+		return nil, false
+	}
+
+	value, ok := p.sessions.Load(sessionID)
+	if !ok {
+		return nil, false
+	}
+
+	stats, ok := value.(*MCPServerSessionStats)
+
+	return stats, ok
+}
+
+type requestTarget struct {
+	spanNameSuffix string
+	spanAttributes []attribute.KeyValue
+	logFields      map[string]any
+	name           string
+}
+
+func describeRequest(method mcp.MCPMethod, message any) requestTarget {
+	target := requestTarget{
+		spanNameSuffix: string(method),
+		logFields:      map[string]any{},
+	}
+
+	switch method {
+	case mcp.MethodResourcesRead:
+		if req, ok := message.(*mcp.ReadResourceRequest); ok {
+			target.spanNameSuffix = fmt.Sprintf("%s %s", target.spanNameSuffix, req.Params.URI)
+			target.spanAttributes = append(target.spanAttributes, attribute.String("mcp.resource", req.Params.URI))
+			target.logFields["mcpResourceURI"] = req.Params.URI
+			target.name = req.Params.URI
+		}
+	case mcp.MethodPromptsGet:
+		if req, ok := message.(*mcp.GetPromptRequest); ok {
+			target.spanNameSuffix = fmt.Sprintf("%s %s", target.spanNameSuffix, req.Params.Name)
+			target.spanAttributes = append(target.spanAttributes, attribute.String("mcp.prompt", req.Params.Name))
+			target.logFields["mcpPrompt"] = req.Params.Name
+			target.name = req.Params.Name
 		}
+	case mcp.MethodToolsCall:
+		if req, ok := message.(*mcp.CallToolRequest); ok {
+			target.spanNameSuffix = fmt.Sprintf("%s %s", target.spanNameSuffix, req.Params.Name)
+			target.spanAttributes = append(target.spanAttributes, attribute.String("mcp.tool", req.Params.Name))
+			target.logFields["mcpTool"] = req.Params.Name
+			target.name = req.Params.Name
+		}
+	case mcp.MethodCompletionComplete:
+		if req, ok := message.(*mcp.CompleteRequest); ok {
+			ref := CompletionReference(req)
+			target.spanNameSuffix = fmt.Sprintf("%s %s", target.spanNameSuffix, ref)
+			target.spanAttributes = append(
+				target.spanAttributes,
+				attribute.String("mcp.completion.ref", ref),
+				attribute.String("mcp.completion.argument", req.Params.Argument.Name),
+			)
+			target.logFields["mcpCompletionRef"] = ref
+			target.logFields["mcpCompletionArgument"] = req.Params.Argument.Name
+			target.name = ref
+		}
+	}
+
+	return target
+}
+
+// ToolResultError returns the error text of a given tool call result, and true if the result is flagged as error.
+func ToolResultError(result any) (string, bool) {
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil || !toolResult.IsError {
+		return "", false
+	}
 
-		if metricsEnabled {
-			p.requestsCounter.WithLabelValues(mcpMethod, metricTarget, "error").Inc()
-			p.requestsDuration.WithLabelValues(mcpMethod, metricTarget).Observe(latency.Seconds())
+	var texts []string
+	for _, content := range toolResult.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
 		}
-	})
+	}
 
-	return hooks
+	if len(texts) == 0 {
+		return "MCP tool error", true
+	}
+
+	return strings.Join(texts, ", "), true
 }
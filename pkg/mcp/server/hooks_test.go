@@ -0,0 +1,393 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type testHooksProvider struct {
+	hooks *server.Hooks
+}
+
+func (p *testHooksProvider) Provide() *server.Hooks {
+	return p.hooks
+}
+
+type testHook struct {
+	name  string
+	calls *[]string
+}
+
+func (h *testHook) Register(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		*h.calls = append(*h.calls, h.name)
+	})
+}
+
+func TestComposeHooks(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	provided := &server.Hooks{}
+	provided.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		calls = append(calls, "provider")
+	})
+
+	hooks := yokaimcpserver.ComposeHooks(
+		&testHooksProvider{hooks: provided},
+		&testHook{name: "first", calls: &calls},
+		&testHook{name: "second", calls: &calls},
+	)
+
+	for _, hook := range hooks.OnBeforeAny {
+		hook(context.Background(), 1, mcp.MethodPing, nil)
+	}
+
+	assert.Equal(t, []string{"provider", "first", "second"}, calls)
+}
+
+func TestComposeHooksWithNilProvidedHooks(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	hooks := yokaimcpserver.ComposeHooks(&testHooksProvider{}, &testHook{name: "first", calls: &calls})
+
+	assert.Len(t, hooks.OnBeforeAny, 1)
+}
+
+func TestToolResultError(t *testing.T) {
+	t.Parallel()
+
+	message, isErr := yokaimcpserver.ToolResultError(mcp.NewToolResultText("ok"))
+	assert.False(t, isErr)
+	assert.Empty(t, message)
+
+	message, isErr = yokaimcpserver.ToolResultError(mcp.NewToolResultError("not found"))
+	assert.True(t, isErr)
+	assert.Equal(t, "not found", message)
+
+	message, isErr = yokaimcpserver.ToolResultError(&mcp.CallToolResult{IsError: true})
+	assert.True(t, isErr)
+	assert.Equal(t, "MCP tool error", message)
+
+	_, isErr = yokaimcpserver.ToolResultError("not a tool result")
+	assert.False(t, isErr)
+}
+
+func TestHooksToolErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	failing := newTestTool("book-fail")
+	failing.handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("book not found"), nil
+	}
+
+	s := newTestHooksServer(t, metricsConfig, newTestTool("book-list"), failing)
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "book-list"})
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "book-fail"})
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "unknown"})
+
+	expected := `
+# HELP mcp_server_requests_total Number of processed MCP requests
+# TYPE mcp_server_requests_total counter
+mcp_server_requests_total{method="initialize",status="success",target=""} 1
+mcp_server_requests_total{method="tools/call",status="error",target="other"} 1
+mcp_server_requests_total{method="tools/call",status="success",target="book-list"} 1
+mcp_server_requests_total{method="tools/call",status="tool_error",target="book-fail"} 1
+`
+	err := testutil.GatherAndCompare(s.prometheus, strings.NewReader(expected), "mcp_server_requests_total")
+	assert.NoError(t, err)
+}
+
+func TestHooksPayloadTruncationAndSizes(t *testing.T) {
+	t.Parallel()
+
+	large := newTestTool("book-large")
+	large.handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("a", 200)), nil
+	}
+
+	s := newTestHooksServer(t, metricsConfig+`
+          sizes: true
+      log:
+        request: true
+        response: true
+        max_payload_size: 20
+        response_size_threshold: 100
+        exclude:
+          - "initialize"
+`, large)
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "book-large"})
+
+	records, err := s.logs.Records()
+	require.NoError(t, err)
+
+	var found bool
+	for _, record := range records {
+		if message, _ := record.Message(); message != "MCP request success" {
+			continue
+		}
+
+		found = true
+
+		request, err := record.Attribute("mcpRequest")
+		require.NoError(t, err)
+		assert.Regexp(t, `^.{20}\.\.\.\(truncated \d+ bytes\)$`, request)
+
+		response, err := record.Attribute("mcpResponse")
+		require.NoError(t, err)
+		assert.Regexp(t, `^.{20}\.\.\.\(truncated \d+ bytes\)$`, response)
+	}
+	assert.True(t, found)
+
+	logtest.AssertHasLogRecord(t, s.logs, map[string]any{
+		"level":                    "warn",
+		"mcpTool":                  "book-large",
+		"mcpResponseSizeThreshold": float64(100),
+		"message":                  "MCP tool result size exceeds threshold",
+	})
+
+	labels := map[string]string{"method": "tools/call", "target": "book-large"}
+
+	responseSize := histogram(t, s.prometheus, "mcp_server_response_size_bytes", labels)
+	require.NotNil(t, responseSize)
+	assert.Equal(t, uint64(1), responseSize.GetSampleCount())
+	assert.Greater(t, responseSize.GetSampleSum(), float64(200))
+
+	requestSize := histogram(t, s.prometheus, "mcp_server_request_size_bytes", labels)
+	require.NotNil(t, requestSize)
+	assert.Equal(t, uint64(1), requestSize.GetSampleCount())
+	assert.Positive(t, requestSize.GetSampleSum())
+}
+
+func TestHooksPayloadSizesDisabled(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, metricsConfig, newTestTool("book-list"))
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "book-list"})
+
+	labels := map[string]string{"method": "tools/call", "target": "book-list"}
+
+	assert.Nil(t, histogram(t, s.prometheus, "mcp_server_request_size_bytes", labels))
+	assert.Nil(t, histogram(t, s.prometheus, "mcp_server_response_size_bytes", labels))
+	assert.NotNil(t, histogram(t, s.prometheus, "mcp_server_requests_duration_seconds", labels))
+}
+
+const benchmarkDisabledConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+      log:
+        exclude:
+          - "tools/call"
+      trace:
+        exclude:
+          - "tools/call"
+      metrics:
+        collect:
+          enabled: false
+`
+
+const benchmarkMetricsConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+      log:
+        exclude:
+          - "tools/call"
+      trace:
+        exclude:
+          - "tools/call"
+      metrics:
+        collect:
+          enabled: true
+`
+
+const benchmarkEnabledConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+      log:
+        request: true
+        response: true
+        max_payload_size: 4096
+      trace:
+        request: true
+        response: true
+        max_payload_size: 4096
+      metrics:
+        collect:
+          enabled: true
+`
+
+func BenchmarkHooksOnSuccessDisabled(b *testing.B) {
+	benchmarkHooksOnSuccess(b, benchmarkDisabledConfig)
+}
+
+func BenchmarkHooksOnSuccessMetricsOnly(b *testing.B) {
+	benchmarkHooksOnSuccess(b, benchmarkMetricsConfig)
+}
+
+func BenchmarkHooksOnSuccessEnabled(b *testing.B) {
+	benchmarkHooksOnSuccess(b, benchmarkEnabledConfig)
+}
+
+// benchmarkHooksOnSuccess measures the hooks of a successful tools/call request, from BeforeAny (root span start) to
+// OnSuccess (payloads, logs, span and metrics).
+func benchmarkHooksOnSuccess(b *testing.B, content string) {
+	b.Helper()
+
+	cfg := mcptest.NewConfig(b, content)
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(cfg, []yokaimcpserver.MCPServerTool{newTestTool("book-list")}, nil, nil, nil, nil, nil, nil)
+	require.NoError(b, err)
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(cfg)
+	require.NoError(b, err)
+
+	hooks := yokaimcpserver.NewDefaultMCPServerHooksProvider(
+		cfg,
+		redactor,
+		yokaimcpserver.NewMCPServerMetricTargetResolver(cfg, registry),
+		yokaimcpserver.NewPrometheusMCPServerMetrics(prometheus.NewRegistry(), cfg),
+	).Provide()
+
+	logger, err := log.NewDefaultLoggerFactory().Create(log.WithLevel(zerolog.InfoLevel), log.WithOutputWriter(io.Discard))
+	require.NoError(b, err)
+
+	tracerProvider, err := trace.NewDefaultTracerProviderFactory().Create(trace.Global(false))
+	require.NoError(b, err)
+
+	ctx := trace.WithContext(logger.WithContext(context.Background()), tracerProvider)
+
+	request := newTestToolRequest("book-list", map[string]any{
+		"genre":    "science-fiction",
+		"password": "secret",
+		"limit":    10,
+	})
+	result := mcp.NewToolResultText(strings.Repeat(`{"id":1,"title":"Dune","author":"Frank Herbert"},`, 50))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		for _, hook := range hooks.OnBeforeAny {
+			hook(ctx, i, mcp.MethodToolsCall, request)
+		}
+
+		for _, hook := range hooks.OnSuccess {
+			hook(ctx, i, mcp.MethodToolsCall, request, result)
+		}
+	}
+}
+
+func TestHooksRequestsWithoutSession(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, allCapabilitiesConfig)
+
+	// two clients without session using the same JSON-RPC request ID
+	first := newTestToolRequest("book-list", nil)
+	second := newTestToolRequest("book-get", nil)
+
+	for _, hook := range s.hooks.OnBeforeAny {
+		hook(s.ctx, 1, mcp.MethodToolsCall, first)
+		hook(s.ctx, 1, mcp.MethodToolsCall, second)
+	}
+
+	for _, hook := range s.hooks.OnSuccess {
+		hook(s.ctx, 1, mcp.MethodToolsCall, second, mcp.NewToolResultText("second"))
+		hook(s.ctx, 1, mcp.MethodToolsCall, first, mcp.NewToolResultText("first"))
+	}
+
+	firstSpan, err := s.spans.Span("MCP tools/call book-list")
+	require.NoError(t, err)
+
+	secondSpan, err := s.spans.Span("MCP tools/call book-get")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, firstSpan.SpanContext.TraceID(), secondSpan.SpanContext.TraceID())
+	assert.Len(t, s.spans.Spans(), 2)
+}
+
+func TestHooksEvictNeverCompletedRequests(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, allCapabilitiesConfig+`
+      trace:
+        span_timeout: 50ms
+`)
+
+	abandoned := newTestToolRequest("book-abandoned", nil)
+	completed := newTestToolRequest("book-completed", nil)
+
+	for _, hook := range s.hooks.OnBeforeAny {
+		hook(s.ctx, 1, mcp.MethodToolsCall, abandoned)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// the next request evicts the abandoned one
+	for _, hook := range s.hooks.OnBeforeAny {
+		hook(s.ctx, 2, mcp.MethodToolsCall, completed)
+	}
+
+	for _, hook := range s.hooks.OnSuccess {
+		hook(s.ctx, 1, mcp.MethodToolsCall, abandoned, mcp.NewToolResultText("late"))
+		hook(s.ctx, 2, mcp.MethodToolsCall, completed, mcp.NewToolResultText("ok"))
+	}
+
+	// the abandoned request span was force ended, and not found anymore on late completion
+	abandonedSpan, err := s.spans.Span("MCP tools/call")
+	require.NoError(t, err)
+	assert.Contains(t, abandonedSpan.Attributes, attribute.Bool("mcp.timeout", true))
+	assert.False(t, s.spans.HasSpan("MCP tools/call book-abandoned"))
+
+	records, err := s.logs.Records()
+	require.NoError(t, err)
+
+	for _, record := range records {
+		tool, _ := record.Attribute("mcpTool")
+		_, err := record.Attribute("traceID")
+
+		switch tool {
+		case "book-abandoned":
+			assert.Error(t, err)
+		case "book-completed":
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.True(t, s.spans.HasSpan("MCP tools/call book-completed"))
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/prometheus/client_golang/prometheus"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	MetricsBackendPrometheus = "prometheus"
+	MetricsBackendOTel       = "otel"
+)
+
+// MCPServerRequestMetric describes a completed MCP request, for metrics recording.
+//
+// The payload sizes are provided only if HasSizes is true, since computing them requires serialising the payloads.
+type MCPServerRequestMetric struct {
+	Method       string
+	Target       string
+	Status       string
+	Latency      time.Duration
+	RequestSize  int
+	ResponseSize int
+	HasSizes     bool
+	HasResponse  bool
+}
+
+// MCPServerMetrics records the MCP server instruments, independently of the metrics backend.
+//
+// The context carries the request span, for backends supporting exemplars.
+type MCPServerMetrics interface {
+	SessionRegistered(ctx context.Context, transport string)
+	SessionInitialized(ctx context.Context, transport string, client string)
+	SessionUnregistered(ctx context.Context, transport string, client string, initialized bool, duration time.Duration)
+	RequestCompleted(ctx context.Context, metric MCPServerRequestMetric)
+}
+
+var _ MCPServerMetrics = (*PrometheusMCPServerMetrics)(nil)
+
+// PrometheusMCPServerMetrics is the MCPServerMetrics implementation based on Prometheus.
+type PrometheusMCPServerMetrics struct {
+	requestsCounter  *prometheus.CounterVec
+	requestsDuration *prometheus.HistogramVec
+	requestsSize     *prometheus.HistogramVec
+	responsesSize    *prometheus.HistogramVec
+	activeSessions   *prometheus.GaugeVec
+	sessionsCounter  *prometheus.CounterVec
+	sessionsDuration *prometheus.HistogramVec
+}
+
+// NewPrometheusMCPServerMetrics returns a new PrometheusMCPServerMetrics, with collectors registered on a given
+// prometheus.Registerer.
+func NewPrometheusMCPServerMetrics(registry prometheus.Registerer, config *config.Config) *PrometheusMCPServerMetrics {
+	namespace := Sanitize(config.GetString("modules.mcp.server.metrics.collect.namespace"))
+	subsystem := Sanitize(config.GetString("modules.mcp.server.metrics.collect.subsystem"))
+
+	var buckets []float64
+	if bucketsConfig := config.GetString("modules.mcp.server.metrics.buckets"); bucketsConfig != "" {
+		for _, s := range Split(bucketsConfig) {
+			f, err := strconv.ParseFloat(s, 64)
+			if err == nil {
+				buckets = append(buckets, f)
+			}
+		}
+	}
+
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	requestsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_requests_total",
+			Help:      "Number of processed MCP requests",
+		},
+		[]string{
+			"method",
+			"target",
+			"status",
+		},
+	)
+
+	requestsDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_requests_duration_seconds",
+			Help:      "Time spent processing MCP requests",
+			Buckets:   buckets,
+		},
+		[]string{
+			"method",
+			"target",
+		},
+	)
+
+	requestsSize := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_request_size_bytes",
+			Help:      "Size of MCP requests",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		},
+		[]string{
+			"method",
+			"target",
+		},
+	)
+
+	responsesSize := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_response_size_bytes",
+			Help:      "Size of MCP responses",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		},
+		[]string{
+			"method",
+			"target",
+		},
+	)
+
+	activeSessions := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_active_sessions",
+			Help:      "Number of active MCP sessions",
+		},
+		[]string{
+			"transport",
+			"client",
+		},
+	)
+
+	sessionsCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_sessions_total",
+			Help:      "Number of MCP sessions",
+		},
+		[]string{
+			"transport",
+			"client",
+		},
+	)
+
+	sessionsDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "mcp_server_session_duration_seconds",
+			Help:      "Duration of MCP sessions",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{
+			"transport",
+			"client",
+		},
+	)
+
+	registry.MustRegister(
+		requestsCounter,
+		requestsDuration,
+		requestsSize,
+		responsesSize,
+		activeSessions,
+		sessionsCounter,
+		sessionsDuration,
+	)
+
+	return &PrometheusMCPServerMetrics{
+		requestsCounter:  requestsCounter,
+		requestsDuration: requestsDuration,
+		requestsSize:     requestsSize,
+		responsesSize:    responsesSize,
+		activeSessions:   activeSessions,
+		sessionsCounter:  sessionsCounter,
+		sessionsDuration: sessionsDuration,
+	}
+}
+
+func (m *PrometheusMCPServerMetrics) SessionRegistered(ctx context.Context, transport string) {
+	m.activeSessions.WithLabelValues(transport, UnknownClientName).Inc()
+}
+
+func (m *PrometheusMCPServerMetrics) SessionInitialized(ctx context.Context, transport string, client string) {
+	m.activeSessions.WithLabelValues(transport, UnknownClientName).Dec()
+	m.activeSessions.WithLabelValues(transport, client).Inc()
+	m.sessionsCounter.WithLabelValues(transport, client).Inc()
+}
+
+func (m *PrometheusMCPServerMetrics) SessionUnregistered(
+	ctx context.Context,
+	transport string,
+	client string,
+	initialized bool,
+	duration time.Duration,
+) {
+	if !initialized {
+		m.sessionsCounter.WithLabelValues(transport, client).Inc()
+	}
+
+	m.activeSessions.WithLabelValues(transport, client).Dec()
+	m.sessionsDuration.WithLabelValues(transport, client).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMCPServerMetrics) RequestCompleted(ctx context.Context, metric MCPServerRequestMetric) {
+	exemplar := Exemplar(oteltrace.SpanFromContext(ctx))
+
+	IncWithExemplar(m.requestsCounter.WithLabelValues(metric.Method, metric.Target, metric.Status), exemplar)
+	ObserveWithExemplar(m.requestsDuration.WithLabelValues(metric.Method, metric.Target), metric.Latency.Seconds(), exemplar)
+
+	if !metric.HasSizes {
+		return
+	}
+
+	ObserveWithExemplar(m.requestsSize.WithLabelValues(metric.Method, metric.Target), float64(metric.RequestSize), exemplar)
+
+	if metric.HasResponse {
+		ObserveWithExemplar(m.responsesSize.WithLabelValues(metric.Method, metric.Target), float64(metric.ResponseSize), exemplar)
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OTelMeterName is the name of the OpenTelemetry meter used by the OTelMCPServerMetrics.
+const OTelMeterName = "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+
+var _ MCPServerMetrics = (*OTelMCPServerMetrics)(nil)
+
+// OTelMCPServerMetrics is the MCPServerMetrics implementation based on an OpenTelemetry meter, following the semantic
+// conventions for RPC servers (rpc.server.*, with rpc.system set to jsonrpc), completed by mcp.server.* instruments.
+type OTelMCPServerMetrics struct {
+	service          string
+	requestsCounter  metric.Int64Counter
+	requestsDuration metric.Float64Histogram
+	requestsSize     metric.Int64Histogram
+	responsesSize    metric.Int64Histogram
+	activeSessions   metric.Int64UpDownCounter
+	sessionsCounter  metric.Int64Counter
+	sessionsDuration metric.Float64Histogram
+}
+
+// NewOTelMCPServerMetrics returns a new OTelMCPServerMetrics, with instruments created from a given
+// metric.MeterProvider.
+func NewOTelMCPServerMetrics(provider metric.MeterProvider, config *config.Config) (*OTelMCPServerMetrics, error) {
+	meter := provider.Meter(OTelMeterName)
+
+	service := config.GetString("modules.mcp.server.name")
+	if service == "" {
+		service = DefaultServerName
+	}
+
+	requestsCounter, errRequestsCounter := meter.Int64Counter(
+		"mcp.server.requests",
+		metric.WithDescription("Number of processed MCP requests"),
+		metric.WithUnit("{request}"),
+	)
+
+	requestsDuration, errRequestsDuration := meter.Float64Histogram(
+		"rpc.server.duration",
+		metric.WithDescription("Time spent processing MCP requests"),
+		metric.WithUnit("ms"),
+	)
+
+	requestsSize, errRequestsSize := meter.Int64Histogram(
+		"rpc.server.request.size",
+		metric.WithDescription("Size of MCP requests"),
+		metric.WithUnit("By"),
+	)
+
+	responsesSize, errResponsesSize := meter.Int64Histogram(
+		"rpc.server.response.size",
+		metric.WithDescription("Size of MCP responses"),
+		metric.WithUnit("By"),
+	)
+
+	activeSessions, errActiveSessions := meter.Int64UpDownCounter(
+		"mcp.server.sessions.active",
+		metric.WithDescription("Number of active MCP sessions"),
+		metric.WithUnit("{session}"),
+	)
+
+	sessionsCounter, errSessionsCounter := meter.Int64Counter(
+		"mcp.server.sessions",
+		metric.WithDescription("Number of MCP sessions"),
+		metric.WithUnit("{session}"),
+	)
+
+	sessionsDuration, errSessionsDuration := meter.Float64Histogram(
+		"mcp.server.session.duration",
+		metric.WithDescription("Duration of MCP sessions"),
+		metric.WithUnit("s"),
+	)
+
+	err := errors.Join(
+		errRequestsCounter,
+		errRequestsDuration,
+		errRequestsSize,
+		errResponsesSize,
+		errActiveSessions,
+		errSessionsCounter,
+		errSessionsDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTelMCPServerMetrics{
+		service:          service,
+		requestsCounter:  requestsCounter,
+		requestsDuration: requestsDuration,
+		requestsSize:     requestsSize,
+		responsesSize:    responsesSize,
+		activeSessions:   activeSessions,
+		sessionsCounter:  sessionsCounter,
+		sessionsDuration: sessionsDuration,
+	}, nil
+}
+
+func (m *OTelMCPServerMetrics) SessionRegistered(ctx context.Context, transport string) {
+	m.activeSessions.Add(ctx, 1, metric.WithAttributes(m.sessionAttributes(transport, UnknownClientName)...))
+}
+
+func (m *OTelMCPServerMetrics) SessionInitialized(ctx context.Context, transport string, client string) {
+	m.activeSessions.Add(ctx, -1, metric.WithAttributes(m.sessionAttributes(transport, UnknownClientName)...))
+	m.activeSessions.Add(ctx, 1, metric.WithAttributes(m.sessionAttributes(transport, client)...))
+	m.sessionsCounter.Add(ctx, 1, metric.WithAttributes(m.sessionAttributes(transport, client)...))
+}
+
+func (m *OTelMCPServerMetrics) SessionUnregistered(
+	ctx context.Context,
+	transport string,
+	client string,
+	initialized bool,
+	duration time.Duration,
+) {
+	attributes := metric.WithAttributes(m.sessionAttributes(transport, client)...)
+
+	if !initialized {
+		m.sessionsCounter.Add(ctx, 1, attributes)
+	}
+
+	m.activeSessions.Add(ctx, -1, attributes)
+	m.sessionsDuration.Record(ctx, duration.Seconds(), attributes)
+}
+
+func (m *OTelMCPServerMetrics) RequestCompleted(ctx context.Context, mcpMetric MCPServerRequestMetric) {
+	attributes := metric.WithAttributes(
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.service", m.service),
+		attribute.String("rpc.method", mcpMetric.Method),
+		attribute.String("mcp.target", mcpMetric.Target),
+		attribute.String("mcp.status", mcpMetric.Status),
+	)
+
+	m.requestsCounter.Add(ctx, 1, attributes)
+	m.requestsDuration.Record(ctx, float64(mcpMetric.Latency)/float64(time.Millisecond), attributes)
+
+	if !mcpMetric.HasSizes {
+		return
+	}
+
+	m.requestsSize.Record(ctx, int64(mcpMetric.RequestSize), attributes)
+
+	if mcpMetric.HasResponse {
+		m.responsesSize.Record(ctx, int64(mcpMetric.ResponseSize), attributes)
+	}
+}
+
+func (m *OTelMCPServerMetrics) sessionAttributes(transport string, client string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.service", m.service),
+		attribute.String("mcp.transport", transport),
+		attribute.String("mcp.client", client),
+	}
+}
@@ -0,0 +1,222 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type testMeasurement struct {
+	instrument string
+	value      float64
+	attributes attribute.Set
+}
+
+// testMeterProvider is a metric.MeterProvider recording the measurements of the synchronous instruments used by the
+// OTelMCPServerMetrics.
+type testMeterProvider struct {
+	embedded.MeterProvider
+	meter *testMeter
+}
+
+func newTestMeterProvider() *testMeterProvider {
+	return &testMeterProvider{
+		meter: &testMeter{},
+	}
+}
+
+func (p *testMeterProvider) Meter(name string, options ...metric.MeterOption) metric.Meter {
+	p.meter.name = name
+
+	return p.meter
+}
+
+type testMeter struct {
+	noop.Meter
+	mutex        sync.Mutex
+	name         string
+	measurements []testMeasurement
+	err          error
+}
+
+func (m *testMeter) record(instrument string, value float64, attributes attribute.Set) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.measurements = append(m.measurements, testMeasurement{instrument, value, attributes})
+}
+
+// find returns the measurements of a given instrument, having given attributes.
+func (m *testMeter) find(instrument string, attributes ...attribute.KeyValue) []float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var values []float64
+
+	for _, measurement := range m.measurements {
+		if measurement.instrument != instrument {
+			continue
+		}
+
+		matched := true
+		for _, kv := range attributes {
+			if value, ok := measurement.attributes.Value(kv.Key); !ok || value != kv.Value {
+				matched = false
+			}
+		}
+
+		if matched {
+			values = append(values, measurement.value)
+		}
+	}
+
+	return values
+}
+
+func (m *testMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &testInt64Instrument{meter: m, name: name}, m.err
+}
+
+func (m *testMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return &testInt64Instrument{meter: m, name: name}, nil
+}
+
+func (m *testMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return &testInt64Instrument{meter: m, name: name}, nil
+}
+
+func (m *testMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &testFloat64Instrument{meter: m, name: name}, nil
+}
+
+type testInt64Instrument struct {
+	noop.Int64Counter
+	noop.Int64UpDownCounter
+	noop.Int64Histogram
+	meter *testMeter
+	name  string
+}
+
+func (i *testInt64Instrument) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	i.meter.record(i.name, float64(incr), metric.NewAddConfig(options).Attributes())
+}
+
+func (i *testInt64Instrument) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	i.meter.record(i.name, float64(value), metric.NewRecordConfig(options).Attributes())
+}
+
+type testFloat64Instrument struct {
+	noop.Float64Histogram
+	meter *testMeter
+	name  string
+}
+
+func (i *testFloat64Instrument) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	i.meter.record(i.name, value, metric.NewRecordConfig(options).Attributes())
+}
+
+func TestOTelMCPServerMetricsRequests(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestMeterProvider()
+
+	metrics, err := yokaimcpserver.NewOTelMCPServerMetrics(provider, mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      name: "books-mcp"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, yokaimcpserver.OTelMeterName, provider.meter.name)
+
+	ctx := context.Background()
+
+	metrics.RequestCompleted(ctx, yokaimcpserver.MCPServerRequestMetric{
+		Method:       "tools/call",
+		Target:       "book-list",
+		Status:       "success",
+		Latency:      1500 * time.Microsecond,
+		RequestSize:  100,
+		ResponseSize: 200,
+		HasSizes:     true,
+		HasResponse:  true,
+	})
+	metrics.RequestCompleted(ctx, yokaimcpserver.MCPServerRequestMetric{
+		Method:      "notifications/initialized",
+		Status:      "success",
+		RequestSize: 50,
+		HasSizes:    true,
+	})
+
+	toolAttributes := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.service", "books-mcp"),
+		attribute.String("rpc.method", "tools/call"),
+		attribute.String("mcp.target", "book-list"),
+		attribute.String("mcp.status", "success"),
+	}
+
+	assert.Equal(t, []float64{1}, provider.meter.find("mcp.server.requests", toolAttributes...))
+	assert.Equal(t, []float64{1.5}, provider.meter.find("rpc.server.duration", toolAttributes...))
+	assert.Equal(t, []float64{100}, provider.meter.find("rpc.server.request.size", toolAttributes...))
+	assert.Equal(t, []float64{200}, provider.meter.find("rpc.server.response.size", toolAttributes...))
+
+	notificationAttribute := attribute.String("rpc.method", "notifications/initialized")
+
+	assert.Equal(t, []float64{1}, provider.meter.find("mcp.server.requests", notificationAttribute))
+	assert.Equal(t, []float64{50}, provider.meter.find("rpc.server.request.size", notificationAttribute))
+	assert.Empty(t, provider.meter.find("rpc.server.response.size", notificationAttribute))
+}
+
+func TestOTelMCPServerMetricsSessions(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestMeterProvider()
+
+	metrics, err := yokaimcpserver.NewOTelMCPServerMetrics(provider, mcptest.NewConfig(t, "app:\n  name: test\n"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// initialized session
+	metrics.SessionRegistered(ctx, "sse")
+	metrics.SessionInitialized(ctx, "sse", "cursor")
+	metrics.SessionUnregistered(ctx, "sse", "cursor", true, 3*time.Second)
+
+	// never initialized session
+	metrics.SessionRegistered(ctx, "sse")
+	metrics.SessionUnregistered(ctx, "sse", yokaimcpserver.UnknownClientName, false, time.Second)
+
+	service := attribute.String("rpc.service", yokaimcpserver.DefaultServerName)
+	unknown := attribute.String("mcp.client", yokaimcpserver.UnknownClientName)
+	cursor := attribute.String("mcp.client", "cursor")
+
+	assert.Len(t, provider.meter.find("mcp.server.sessions.active", service), 6)
+	assert.Equal(t, []float64{1, -1, 1, -1}, provider.meter.find("mcp.server.sessions.active", unknown))
+	assert.Equal(t, []float64{1, -1}, provider.meter.find("mcp.server.sessions.active", cursor))
+	assert.Equal(t, []float64{1}, provider.meter.find("mcp.server.sessions", cursor))
+	assert.Equal(t, []float64{1}, provider.meter.find("mcp.server.sessions", unknown))
+	assert.Equal(t, []float64{3}, provider.meter.find("mcp.server.session.duration", cursor))
+	assert.Equal(t, []float64{1}, provider.meter.find("mcp.server.session.duration", unknown))
+}
+
+func TestOTelMCPServerMetricsInstrumentError(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestMeterProvider()
+	provider.meter.err = errors.New("instrument error")
+
+	_, err := yokaimcpserver.NewOTelMCPServerMetrics(provider, mcptest.NewConfig(t, "app:\n  name: test\n"))
+	assert.ErrorContains(t, err, "instrument error")
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MCPServerToolMiddleware wraps tool handlers: it can short-circuit the call, modify the request arguments, or rewrite
+// the result returned by the next handler.
+type MCPServerToolMiddleware interface {
+	Name() string
+	Handle(next server.ToolHandlerFunc) server.ToolHandlerFunc
+}
+
+// MCPServerPromptMiddleware wraps prompt handlers: it can short-circuit the call, modify the request arguments, or
+// rewrite the result returned by the next handler.
+type MCPServerPromptMiddleware interface {
+	Name() string
+	Handle(next server.PromptHandlerFunc) server.PromptHandlerFunc
+}
+
+// MCPServerResourceMiddleware wraps resource and resource template handlers: it can short-circuit the call, modify the
+// request, or rewrite the contents returned by the next handler.
+type MCPServerResourceMiddleware interface {
+	Name() string
+	Handle(next server.ResourceHandlerFunc) server.ResourceHandlerFunc
+}
+
+// OrderMiddlewares returns the middlewares listed in the provided order first, followed by the remaining ones in
+// their registration order. The first middleware of the result is the outermost one.
+//
+// Names listed several times are applied once, at their first position, and names matching no middleware are
+// rejected with an error.
+func OrderMiddlewares[M interface{ Name() string }](middlewares []M, order []string) ([]M, error) {
+	ordered := make([]M, 0, len(middlewares))
+	listed := make(map[string]bool, len(order))
+
+	for _, name := range order {
+		if listed[name] {
+			continue
+		}
+
+		listed[name] = true
+
+		found := false
+
+		for _, middleware := range middlewares {
+			if middleware.Name() == name {
+				ordered = append(ordered, middleware)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown MCP middleware %q in order", name)
+		}
+	}
+
+	for _, middleware := range middlewares {
+		if !listed[middleware.Name()] {
+			ordered = append(ordered, middleware)
+		}
+	}
+
+	return ordered, nil
+}
+
+func applyToolMiddlewares(handler server.ToolHandlerFunc, middlewares []MCPServerToolMiddleware) server.ToolHandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Handle(handler)
+	}
+
+	return handler
+}
+
+func applyPromptMiddlewares(handler server.PromptHandlerFunc, middlewares []MCPServerPromptMiddleware) server.PromptHandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Handle(handler)
+	}
+
+	return handler
+}
+
+func applyResourceMiddlewares(handler server.ResourceHandlerFunc, middlewares []MCPServerResourceMiddleware) server.ResourceHandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Handle(handler)
+	}
+
+	return handler
+}
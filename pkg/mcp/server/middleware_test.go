@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testToolMiddleware struct {
+	name string
+}
+
+func (m *testToolMiddleware) Name() string {
+	return m.name
+}
+
+func (m *testToolMiddleware) Handle(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if m.name == "blocker" && request.GetString("block", "") == "yes" {
+			return mcp.NewToolResultError("blocked"), nil
+		}
+
+		result, err := next(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		text := result.Content[0].(mcp.TextContent).Text
+
+		return mcp.NewToolResultText(strings.Join([]string{m.name, text}, ">")), nil
+	}
+}
+
+func TestOrderMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	middlewares := []*testToolMiddleware{{name: "a"}, {name: "b"}, {name: "c"}}
+
+	ordered, err := yokaimcpserver.OrderMiddlewares(middlewares, []string{"c", "a", "c"})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(ordered))
+	for _, middleware := range ordered {
+		names = append(names, middleware.Name())
+	}
+
+	assert.Equal(t, []string{"c", "a", "b"}, names)
+
+	_, err = yokaimcpserver.OrderMiddlewares(middlewares, []string{"c", "unknwon", "a"})
+	assert.EqualError(t, err, `unknown MCP middleware "unknwon" in order`)
+}
+
+func TestMCPServerRegistryUnknownMiddleware(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      middlewares:
+        prompts:
+          - unknown
+`)
+
+	_, err := yokaimcpserver.NewMCPServerRegistry(cfg, nil, nil, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, `invalid MCP prompts middlewares: unknown MCP middleware "unknown" in order`)
+}
+
+func TestMCPServerRegistryToolMiddlewares(t *testing.T) {
+	t.Parallel()
+
+	cfg := mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+      middlewares:
+        tools:
+          - second
+`)
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		cfg,
+		[]yokaimcpserver.MCPServerTool{newTestTool("book-list")},
+		nil,
+		nil,
+		nil,
+		[]yokaimcpserver.MCPServerToolMiddleware{
+			&testToolMiddleware{name: "first"},
+			&testToolMiddleware{name: "blocker"},
+			&testToolMiddleware{name: "second"},
+		},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	registry.Register(mcpServer)
+
+	// the first middleware is the outermost one
+	assert.Equal(t, "second>first>blocker>book-list", callTool(t, mcpServer, "book-list", nil))
+
+	// a middleware can short-circuit the call
+	assert.Equal(t, "second>first>blocked", callTool(t, mcpServer, "book-list", map[string]any{"block": "yes"}))
+
+	// middlewares also apply to tools added at runtime
+	require.NoError(t, registry.AddTool(newTestTool("book-get")))
+	assert.Equal(t, "second>first>blocker>book-get", callTool(t, mcpServer, "book-get", nil))
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MCPServerPayload is a MCP payload serialised on first access only, to avoid the cost of the serialisation when no
+// observability consumer needs it. The redaction is applied on top of the serialisation, only when the payload content
+// is needed (logs or traces), since sizes can be computed from the raw serialisation.
+type MCPServerPayload struct {
+	redactor   *MCPServerRedactor
+	value      any
+	raw        []byte
+	rawErr     error
+	serialised bool
+	data       []byte
+	err        error
+	redacted   bool
+}
+
+// NewMCPServerPayload returns a new MCPServerPayload, for a given value.
+func NewMCPServerPayload(redactor *MCPServerRedactor, value any) *MCPServerPayload {
+	return &MCPServerPayload{
+		redactor: redactor,
+		value:    value,
+	}
+}
+
+// Raw returns the JSON representation of the payload, serialised once, without redaction.
+func (p *MCPServerPayload) Raw() ([]byte, error) {
+	if !p.serialised {
+		p.raw, p.rawErr = json.Marshal(p.value)
+		p.serialised = true
+	}
+
+	return p.raw, p.rawErr
+}
+
+// Bytes returns the redacted JSON representation of the payload, redacted once.
+func (p *MCPServerPayload) Bytes() ([]byte, error) {
+	if !p.redacted {
+		raw, err := p.Raw()
+		if err == nil {
+			p.data, p.err = p.redactor.Redact(p.value, raw)
+		} else {
+			p.err = err
+		}
+
+		p.redacted = true
+	}
+
+	return p.data, p.err
+}
+
+// String returns the redacted JSON representation of the payload, truncated to a given size, and false on failure.
+func (p *MCPServerPayload) String(size int) (string, bool) {
+	data, err := p.Bytes()
+	if err != nil {
+		return "", false
+	}
+
+	return Truncate(string(data), size), true
+}
+
+// Size returns the size in bytes of the JSON representation of the payload (before redaction), or 0 on failure.
+func (p *MCPServerPayload) Size() int {
+	raw, err := p.Raw()
+	if err != nil {
+		return 0
+	}
+
+	return len(raw)
+}
+
+// MCPServerHooksSettings holds the observability settings of the hooks, resolved once from config.
+type MCPServerHooksSettings struct {
+	TraceRequest          bool
+	TraceResponse         bool
+	TraceMaxPayloadSize   int
+	TraceSpanTimeout      time.Duration
+	LogRequest            bool
+	LogResponse           bool
+	LogMaxPayloadSize     int
+	ResponseSizeThreshold int
+	MetricsEnabled        bool
+	MetricsSizesEnabled   bool
+	traceExclusions       map[string]struct{}
+	logExclusions         map[string]struct{}
+	metricsClients        map[string]struct{}
+}
+
+// NewMCPServerHooksSettings returns a new MCPServerHooksSettings, resolved from modules.mcp.server.trace, log and metrics.
+func NewMCPServerHooksSettings(config *config.Config) *MCPServerHooksSettings {
+	settings := &MCPServerHooksSettings{
+		TraceRequest:          config.GetBool("modules.mcp.server.trace.request"),
+		TraceResponse:         config.GetBool("modules.mcp.server.trace.response"),
+		TraceMaxPayloadSize:   config.GetInt("modules.mcp.server.trace.max_payload_size"),
+		TraceSpanTimeout:      yokaimcpservercontext.DefaultRootSpanTimeout,
+		LogRequest:            config.GetBool("modules.mcp.server.log.request"),
+		LogResponse:           config.GetBool("modules.mcp.server.log.response"),
+		LogMaxPayloadSize:     config.GetInt("modules.mcp.server.log.max_payload_size"),
+		ResponseSizeThreshold: config.GetInt("modules.mcp.server.log.response_size_threshold"),
+		MetricsEnabled:        config.GetBool("modules.mcp.server.metrics.collect.enabled"),
+		MetricsSizesEnabled:   config.GetBool("modules.mcp.server.metrics.collect.sizes"),
+		traceExclusions:       toSet(config.GetStringSlice("modules.mcp.server.trace.exclude")),
+		logExclusions:         toSet(config.GetStringSlice("modules.mcp.server.log.exclude")),
+		metricsClients:        toSet(config.GetStringSlice("modules.mcp.server.metrics.clients.allow")),
+	}
+
+	if config.IsSet("modules.mcp.server.trace.span_timeout") {
+		settings.TraceSpanTimeout = config.GetDuration("modules.mcp.server.trace.span_timeout")
+	}
+
+	return settings
+}
+
+// Trace returns true if a given MCP method is traced.
+func (s *MCPServerHooksSettings) Trace(method mcp.MCPMethod) bool {
+	_, excluded := s.traceExclusions[string(method)]
+
+	return !excluded
+}
+
+// Log returns true if a given MCP method is logged.
+func (s *MCPServerHooksSettings) Log(method mcp.MCPMethod) bool {
+	_, excluded := s.logExclusions[string(method)]
+
+	return !excluded
+}
+
+// CheckResponseSize returns true if the response size of a given MCP method must be checked against the threshold.
+func (s *MCPServerHooksSettings) CheckResponseSize(method mcp.MCPMethod) bool {
+	return method == mcp.MethodToolsCall && s.ResponseSizeThreshold > 0
+}
+
+// MetricClient returns the bounded metric client label value for a given client name: clients not listed in
+// modules.mcp.server.metrics.clients.allow fall into the "other" bucket, since client names are client supplied.
+func (s *MCPServerHooksSettings) MetricClient(clientName string) string {
+	if clientName == UnknownClientName {
+		return clientName
+	}
+
+	if _, allowed := s.metricsClients[clientName]; allowed {
+		return clientName
+	}
+
+	return OtherClientName
+}
+
+func toSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+	for _, item := range list {
+		set[item] = struct{}{}
+	}
+
+	return set
+}
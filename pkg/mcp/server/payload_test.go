@@ -0,0 +1,59 @@
+package server_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServerPayload(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, "app:\n  name: test\n"))
+	require.NoError(t, err)
+
+	request := newTestToolRequest("book-list", map[string]any{"password": "secret", "genre": "horror"})
+
+	raw, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	payload := yokaimcpserver.NewMCPServerPayload(redactor, request)
+
+	data, err := payload.Raw()
+	require.NoError(t, err)
+	assert.Equal(t, raw, data)
+	assert.Equal(t, len(raw), payload.Size())
+
+	str, ok := payload.String(0)
+	assert.True(t, ok)
+	assert.Contains(t, str, `"password":"[REDACTED]"`)
+	assert.Contains(t, str, `"genre":"horror"`)
+
+	str, ok = payload.String(10)
+	assert.True(t, ok)
+	assert.Contains(t, str, "...(truncated")
+}
+
+func TestMCPServerPayloadSizeWithoutRedaction(t *testing.T) {
+	t.Parallel()
+
+	// sizes do not need the redaction, so a payload without redactor can still be measured
+	payload := yokaimcpserver.NewMCPServerPayload(nil, map[string]any{"genre": "horror"})
+
+	assert.Equal(t, len(`{"genre":"horror"}`), payload.Size())
+}
+
+func TestMCPServerPayloadWithInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	payload := yokaimcpserver.NewMCPServerPayload(nil, map[string]any{"invalid": make(chan int)})
+
+	assert.Equal(t, 0, payload.Size())
+
+	_, ok := payload.String(0)
+	assert.False(t, ok)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"reflect"
+
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RequestMeta returns the _meta of a given MCP request message, for any request type exposing Params.Meta, or nil.
+func RequestMeta(message any) *mcp.Meta {
+	v := reflect.ValueOf(message)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	params := v.FieldByName("Params")
+	if !params.IsValid() || params.Kind() != reflect.Struct {
+		return nil
+	}
+
+	meta := params.FieldByName("Meta")
+	if !meta.IsValid() || !meta.CanInterface() {
+		return nil
+	}
+
+	m, _ := meta.Interface().(*mcp.Meta)
+
+	return m
+}
+
+// withRequestTraceContext returns a context carrying the W3C trace context and baggage found in the _meta of a given
+// MCP request message, for transports creating their context once per connection (like stdio).
+func withRequestTraceContext(ctx context.Context, message any) context.Context {
+	meta := RequestMeta(message)
+	if meta == nil {
+		return ctx
+	}
+
+	return yokaimcpservercontext.WithMetaTraceContext(ctx, meta.AdditionalFields)
+}
@@ -0,0 +1,73 @@
+package server_test
+
+import (
+	"testing"
+
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID    = "00f067aa0ba902b7"
+	testTraceParent = "00-" + testTraceID + "-" + testParentID + "-01"
+)
+
+func TestRequestMeta(t *testing.T) {
+	t.Parallel()
+
+	meta := &mcp.Meta{AdditionalFields: map[string]any{"traceparent": testTraceParent}}
+
+	toolRequest := newTestToolRequest("book-list", nil)
+	toolRequest.Params.Meta = meta
+	assert.Same(t, meta, yokaimcpserver.RequestMeta(toolRequest))
+
+	pingRequest := &mcp.PingRequest{}
+	pingRequest.Params.Meta = meta
+	assert.Same(t, meta, yokaimcpserver.RequestMeta(pingRequest))
+
+	assert.Nil(t, yokaimcpserver.RequestMeta(&mcp.CallToolRequest{}))
+	assert.Nil(t, yokaimcpserver.RequestMeta((*mcp.CallToolRequest)(nil)))
+	assert.Nil(t, yokaimcpserver.RequestMeta(nil))
+	assert.Nil(t, yokaimcpserver.RequestMeta("not a request"))
+	assert.Nil(t, yokaimcpserver.RequestMeta(struct{ Params string }{}))
+}
+
+func TestHooksRootSpanParentFromMeta(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, allCapabilitiesConfig, newTestTool("book-list"))
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{
+		"name":  "book-list",
+		"_meta": map[string]any{"traceparent": testTraceParent},
+	})
+	s.handle(t, ctx, string(mcp.MethodPing), map[string]any{
+		"_meta": map[string]any{"traceparent": testTraceParent},
+	})
+	s.handle(t, ctx, string(mcp.MethodToolsList), map[string]any{})
+
+	toolSpan, err := s.spans.Span("MCP tools/call book-list")
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, toolSpan.SpanContext.TraceID().String())
+	assert.Equal(t, testParentID, toolSpan.Parent.SpanID().String())
+	assert.True(t, toolSpan.Parent.IsRemote())
+	assert.Empty(t, toolSpan.Links)
+
+	pingSpan, err := s.spans.Span("MCP ping")
+	require.NoError(t, err)
+	assert.Equal(t, testTraceID, pingSpan.SpanContext.TraceID().String())
+	assert.Equal(t, testParentID, pingSpan.Parent.SpanID().String())
+
+	listSpan, err := s.spans.Span("MCP tools/list")
+	require.NoError(t, err)
+	assert.NotEqual(t, testTraceID, listSpan.SpanContext.TraceID().String())
+	assert.False(t, listSpan.Parent.IsValid())
+
+	// the root span is the only request span, the tool handler does not create a linked one
+	assert.False(t, s.spans.HasSpan("MCP tools/call"))
+	assert.Len(t, s.spans.Spans(), 4)
+}
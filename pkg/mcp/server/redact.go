@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultRedactionReplacement is the default replacement of redacted values.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// DefaultRedactionKeys is the default deny-list of secret names, redacted wherever found in payloads.
+var DefaultRedactionKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"client_secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"credentials",
+	"private_key",
+	"cookie",
+	"session_token",
+	"credit_card",
+	"card_number",
+	"cvv",
+	"ssn",
+}
+
+// MCPServerRedactor redacts sensitive data from MCP payloads before they are logged or traced, using:
+//   - keys: object keys (case, dash and underscore insensitive) whose values are redacted wherever found
+//   - tools: per tool argument names (case, dash and underscore insensitive) whose values are redacted in the
+//     tools/call requests of this tool, matched case-insensitively on the tool name
+//   - paths: dot separated JSON paths from the payload root (with * wildcard segments) whose values are redacted
+//   - patterns: regular expressions whose matches are redacted in string values, and in error messages
+type MCPServerRedactor struct {
+	keys        map[string]struct{}
+	tools       map[string]map[string]struct{}
+	paths       [][]string
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// NewMCPServerRedactor returns a new MCPServerRedactor, configured from modules.mcp.server.redaction.
+func NewMCPServerRedactor(config *config.Config) (*MCPServerRedactor, error) {
+	replacement := config.GetString("modules.mcp.server.redaction.replacement")
+	if replacement == "" {
+		replacement = DefaultRedactionReplacement
+	}
+
+	redactor := &MCPServerRedactor{
+		keys:        map[string]struct{}{},
+		tools:       map[string]map[string]struct{}{},
+		replacement: replacement,
+	}
+
+	if !config.GetBool("modules.mcp.server.redaction.disable_defaults") {
+		for _, key := range DefaultRedactionKeys {
+			redactor.keys[normalizeRedactionKey(key)] = struct{}{}
+		}
+	}
+
+	for _, key := range config.GetStringSlice("modules.mcp.server.redaction.keys") {
+		redactor.keys[normalizeRedactionKey(key)] = struct{}{}
+	}
+
+	// config map keys are lowercased, tool names are then matched case-insensitively
+	for tool, arguments := range config.GetStringMapStringSlice("modules.mcp.server.redaction.tools") {
+		tool = strings.ToLower(tool)
+
+		if _, ok := redactor.tools[tool]; !ok {
+			redactor.tools[tool] = map[string]struct{}{}
+		}
+
+		for _, argument := range arguments {
+			redactor.tools[tool][normalizeRedactionKey(argument)] = struct{}{}
+		}
+	}
+
+	for _, path := range config.GetStringSlice("modules.mcp.server.redaction.paths") {
+		redactor.paths = append(redactor.paths, strings.Split(path, "."))
+	}
+
+	for _, pattern := range config.GetStringSlice("modules.mcp.server.redaction.patterns") {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP redaction pattern %q: %w", pattern, err)
+		}
+
+		redactor.patterns = append(redactor.patterns, re)
+	}
+
+	return redactor, nil
+}
+
+// Marshal returns the redacted JSON representation of a given MCP payload.
+func (r *MCPServerRedactor) Marshal(payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Redact(payload, data)
+}
+
+// Redact returns the redacted version of a given JSON representation of a given MCP payload.
+func (r *MCPServerRedactor) Redact(payload any, data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	var toolArguments map[string]struct{}
+	if req, ok := payload.(*mcp.CallToolRequest); ok {
+		toolArguments = r.tools[strings.ToLower(req.Params.Name)]
+	}
+
+	value = r.redact(value, nil, toolArguments)
+
+	return json.Marshal(value)
+}
+
+// RedactString returns a given string with the matches of the redaction patterns replaced, for free text like error
+// messages.
+func (r *MCPServerRedactor) RedactString(str string) string {
+	for _, pattern := range r.patterns {
+		str = pattern.ReplaceAllString(str, r.replacement)
+	}
+
+	return str
+}
+
+func (r *MCPServerRedactor) redact(value any, path []string, toolArguments map[string]struct{}) any {
+	if r.matchPath(path) {
+		return r.replacement
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		isToolArguments := len(path) == 2 && path[0] == "params" && path[1] == "arguments"
+
+		for key, item := range v {
+			if _, ok := r.keys[normalizeRedactionKey(key)]; ok {
+				v[key] = r.replacement
+
+				continue
+			}
+
+			if isToolArguments {
+				if _, ok := toolArguments[normalizeRedactionKey(key)]; ok {
+					v[key] = r.replacement
+
+					continue
+				}
+			}
+
+			v[key] = r.redact(item, append(path[:len(path):len(path)], key), toolArguments)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.redact(item, append(path[:len(path):len(path)], "*"), toolArguments)
+		}
+
+		return v
+	case string:
+		return r.RedactString(v)
+	default:
+		return v
+	}
+}
+
+func (r *MCPServerRedactor) matchPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	for _, rule := range r.paths {
+		if len(rule) != len(path) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && segment != path[i] {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeRedactionKey(key string) string {
+	key = strings.ReplaceAll(key, "-", "")
+	key = strings.ReplaceAll(key, "_", "")
+
+	return strings.ToLower(key)
+}
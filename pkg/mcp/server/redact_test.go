@@ -0,0 +1,182 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace/tracetest"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const redactionConfig = `
+modules:
+  mcp:
+    server:
+      redaction:
+        replacement: "***"
+        keys:
+          - "x-internal-id"
+        tools:
+          Create-Book:
+            - "ISBN_Code"
+        paths:
+          - "params.arguments.items.*.price"
+        patterns:
+          - "[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{4}"
+`
+
+func newTestToolRequest(name string, arguments map[string]any) *mcp.CallToolRequest {
+	request := &mcp.CallToolRequest{}
+	request.Method = string(mcp.MethodToolsCall)
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+
+	return request
+}
+
+func TestMCPServerRedactorMarshal(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, redactionConfig))
+	require.NoError(t, err)
+
+	data, err := redactor.Marshal(newTestToolRequest("Create-Book", map[string]any{
+		"title":         "Dune",
+		"Password":      "secret",
+		"X_Internal_ID": "42",
+		"isbn-code":     "978-0441013593",
+		"items":         []any{map[string]any{"price": 10, "name": "paperback"}},
+		"note":          "paid with 1234-5678-9012-3456",
+	}))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"method": "tools/call",
+		"params": {
+			"name": "Create-Book",
+			"arguments": {
+				"title": "Dune",
+				"Password": "***",
+				"X_Internal_ID": "***",
+				"isbn-code": "***",
+				"items": [{"price": "***", "name": "paperback"}],
+				"note": "paid with ***"
+			}
+		}
+	}`, string(data))
+}
+
+func TestMCPServerRedactorMarshalOtherTool(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, redactionConfig))
+	require.NoError(t, err)
+
+	data, err := redactor.Marshal(newTestToolRequest("list-books", map[string]any{"isbn_code": "978-0441013593"}))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"isbn_code":"978-0441013593"`)
+}
+
+func TestMCPServerRedactorDefaults(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, "app:\n  name: test\n"))
+	require.NoError(t, err)
+
+	data, err := redactor.Marshal(map[string]any{"access-token": "abc", "user": "john"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"access-token": "[REDACTED]", "user": "john"}`, string(data))
+
+	redactor, err = yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      redaction:
+        disable_defaults: true
+`))
+	require.NoError(t, err)
+
+	data, err = redactor.Marshal(map[string]any{"access-token": "abc"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"access-token": "abc"}`, string(data))
+}
+
+func TestMCPServerRedactorRedactString(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, redactionConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, "card *** declined", redactor.RedactString("card 1234-5678-9012-3456 declined"))
+	assert.Equal(t, "nothing to redact", redactor.RedactString("nothing to redact"))
+}
+
+func TestNewMCPServerRedactorWithInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := yokaimcpserver.NewMCPServerRedactor(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      redaction:
+        patterns:
+          - "[invalid"
+`))
+	assert.ErrorContains(t, err, `invalid MCP redaction pattern "[invalid"`)
+}
+
+func TestHooksRedactErrorMessages(t *testing.T) {
+	t.Parallel()
+
+	toolError := newTestTool("tool-error")
+	toolError.handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("card 1234-5678-9012-3456 declined"), nil
+	}
+
+	handlerError := newTestTool("handler-error")
+	handlerError.handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("card 1234-5678-9012-3456 rejected")
+	}
+
+	s := newTestHooksServer(t, redactionConfig+`
+      capabilities:
+        tools: true
+`, toolError, handlerError)
+	ctx := s.connect(t, newTestSession("s1"), "cursor")
+
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "tool-error"})
+	s.handle(t, ctx, string(mcp.MethodToolsCall), map[string]any{"name": "handler-error"})
+
+	logtest.AssertHasLogRecord(t, s.logs, map[string]any{
+		"level":    "warn",
+		"mcpTool":  "tool-error",
+		"mcpError": "card *** declined",
+	})
+	logtest.AssertHasLogRecord(t, s.logs, map[string]any{
+		"level":    "error",
+		"mcpTool":  "handler-error",
+		"mcpError": "request error: card *** rejected",
+	})
+
+	tracetest.AssertHasTraceSpan(t, s.spans, "MCP tools/call tool-error", attribute.String("mcp.error", "card *** declined"))
+	tracetest.AssertHasTraceSpan(t, s.spans, "MCP tools/call handler-error", attribute.String("mcp.error", "request error: card *** rejected"))
+
+	for _, span := range s.spans.Spans() {
+		assert.NotContains(t, span.Status.Description, "1234-5678-9012-3456")
+
+		for _, event := range span.Events {
+			for _, attr := range event.Attributes {
+				assert.NotContains(t, attr.Value.Emit(), "1234-5678-9012-3456")
+			}
+		}
+	}
+}
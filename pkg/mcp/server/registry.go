@@ -1,11 +1,22 @@
 package server
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
 	"github.com/ankorstore/yokai/config"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/yosida95/uritemplate/v3"
 )
 
+// ErrFiltered is returned, wrapped with the filter reason, when a capability added at runtime is filtered out by the
+// include and exclude patterns, the disabled capabilities or the read-only mode.
+var ErrFiltered = errors.New("filtered out")
+
 type MCPServerTool interface {
 	Name() string
 	Options() []mcp.ToolOption
@@ -33,12 +44,19 @@ type MCPServerResourceTemplate interface {
 }
 
 type MCPServerRegistryInfo struct {
+	ReadOnly     bool
 	Capabilities struct {
 		Tools     bool
 		Prompts   bool
 		Resources bool
 	}
 	Registrations struct {
+		Tools             map[string]MCPServerToolInfo
+		Prompts           map[string]MCPServerPromptInfo
+		Resources         map[string]MCPServerResourceInfo
+		ResourceTemplates map[string]MCPServerResourceTemplateInfo
+	}
+	Filtered struct {
 		Tools             map[string]string
 		Prompts           map[string]string
 		Resources         map[string]string
@@ -46,12 +64,57 @@ type MCPServerRegistryInfo struct {
 	}
 }
 
+type MCPServerToolInfo struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	InputSchema any                `json:"inputSchema"`
+	Annotations mcp.ToolAnnotation `json:"annotations"`
+	Type        string             `json:"type"`
+	Source      string             `json:"source"`
+}
+
+type MCPServerPromptInfo struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Arguments   []mcp.PromptArgument `json:"arguments"`
+	Type        string               `json:"type"`
+	Source      string               `json:"source"`
+}
+
+type MCPServerResourceInfo struct {
+	Name        string `json:"name"`
+	URI         string `json:"uri"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+}
+
+type MCPServerResourceTemplateInfo struct {
+	Name        string `json:"name"`
+	URITemplate string `json:"uriTemplate"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+}
+
 type MCPServerRegistry struct {
-	config            *config.Config
-	tools             map[string]MCPServerTool
-	prompts           map[string]MCPServerPrompt
-	resources         map[string]MCPServerResource
-	resourceTemplates map[string]MCPServerResourceTemplate
+	mutex                     sync.RWMutex
+	config                    *config.Config
+	mcpServer                 *server.MCPServer
+	tools                     map[string]MCPServerTool
+	prompts                   map[string]MCPServerPrompt
+	resources                 map[string]MCPServerResource
+	resourceTemplates         map[string]MCPServerResourceTemplate
+	filteredTools             map[string]string
+	filteredPrompts           map[string]string
+	filteredResources         map[string]string
+	filteredResourceTemplates map[string]string
+	toolMiddlewares           []MCPServerToolMiddleware
+	promptMiddlewares         []MCPServerPromptMiddleware
+	resourceMiddlewares       []MCPServerResourceMiddleware
+	uriTemplates              sync.Map
 }
 
 func NewMCPServerRegistry(
@@ -60,95 +123,429 @@ func NewMCPServerRegistry(
 	prompts []MCPServerPrompt,
 	resources []MCPServerResource,
 	resourceTemplates []MCPServerResourceTemplate,
-) *MCPServerRegistry {
-	toolsMap := make(map[string]MCPServerTool, len(tools))
-	promptsMap := make(map[string]MCPServerPrompt, len(prompts))
-	resourcesMap := make(map[string]MCPServerResource, len(resources))
-	resourceTemplatesMap := make(map[string]MCPServerResourceTemplate, len(resourceTemplates))
+	toolMiddlewares []MCPServerToolMiddleware,
+	promptMiddlewares []MCPServerPromptMiddleware,
+	resourceMiddlewares []MCPServerResourceMiddleware,
+) (*MCPServerRegistry, error) {
+	err := ValidateMCPServerRegistrations(tools, prompts, resources, resourceTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range MCPServerRegistrationFilterKeys {
+		if err = NewMCPServerRegistrationFilter(config, key).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	orderedToolMiddlewares, err := OrderMiddlewares(toolMiddlewares, config.GetStringSlice("modules.mcp.server.middlewares.tools"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP tools middlewares: %w", err)
+	}
+
+	orderedPromptMiddlewares, err := OrderMiddlewares(promptMiddlewares, config.GetStringSlice("modules.mcp.server.middlewares.prompts"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP prompts middlewares: %w", err)
+	}
+
+	orderedResourceMiddlewares, err := OrderMiddlewares(resourceMiddlewares, config.GetStringSlice("modules.mcp.server.middlewares.resources"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP resources middlewares: %w", err)
+	}
+
+	registry := &MCPServerRegistry{
+		config:                    config,
+		tools:                     make(map[string]MCPServerTool, len(tools)),
+		prompts:                   make(map[string]MCPServerPrompt, len(prompts)),
+		resources:                 make(map[string]MCPServerResource, len(resources)),
+		resourceTemplates:         make(map[string]MCPServerResourceTemplate, len(resourceTemplates)),
+		filteredTools:             make(map[string]string),
+		filteredPrompts:           make(map[string]string),
+		filteredResources:         make(map[string]string),
+		filteredResourceTemplates: make(map[string]string),
+		toolMiddlewares:           orderedToolMiddlewares,
+		promptMiddlewares:         orderedPromptMiddlewares,
+		resourceMiddlewares:       orderedResourceMiddlewares,
+	}
 
 	for _, tool := range tools {
-		toolsMap[tool.Name()] = tool
+		registry.addTool(tool)
 	}
 
 	for _, prompt := range prompts {
-		promptsMap[prompt.Name()] = prompt
+		registry.addPrompt(prompt)
 	}
 
 	for _, resource := range resources {
-		resourcesMap[resource.Name()] = resource
+		registry.addResource(resource)
 	}
 
 	for _, resourceTemplate := range resourceTemplates {
-		resourceTemplatesMap[resourceTemplate.Name()] = resourceTemplate
+		registry.addResourceTemplate(resourceTemplate)
 	}
 
-	return &MCPServerRegistry{
-		config:            config,
-		tools:             toolsMap,
-		prompts:           promptsMap,
-		resources:         resourcesMap,
-		resourceTemplates: resourceTemplatesMap,
-	}
+	return registry, nil
 }
 
+// Register registers the allowed tools, prompts, resources and resource templates on the provided MCP server.
+//
+// The MCP server is kept, so that runtime additions and removals are applied to it as well.
 func (r *MCPServerRegistry) Register(mcpServer *server.MCPServer) {
-	if r.config.GetBool("modules.mcp.server.capabilities.tools") {
-		for _, tool := range r.tools {
-			mcpServer.AddTool(
-				mcp.NewTool(tool.Name(), tool.Options()...),
-				tool.Handle(),
-			)
-		}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.mcpServer = mcpServer
+
+	for _, tool := range r.tools {
+		r.registerTool(tool)
+	}
+
+	for _, prompt := range r.prompts {
+		r.registerPrompt(prompt)
+	}
+
+	for _, resource := range r.resources {
+		r.registerResource(resource)
 	}
 
-	if r.config.GetBool("modules.mcp.server.capabilities.prompts") {
-		for _, prompt := range r.prompts {
-			mcpServer.AddPrompt(
-				mcp.NewPrompt(prompt.Name(), prompt.Options()...),
-				prompt.Handle(),
-			)
+	if len(r.resourceTemplates) > 0 {
+		r.registerResourceTemplates()
+	}
+}
+
+// AddTool adds a tool at runtime, and notifies the connected sessions if the tools list changed. It returns an error
+// wrapping ErrFiltered if the tool is filtered out.
+func (r *MCPServerRegistry) AddTool(tool MCPServerTool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	tools := make([]MCPServerTool, 0, len(r.tools)+1)
+	for _, t := range r.tools {
+		tools = append(tools, t)
+	}
+
+	err := ValidateMCPServerRegistrations(append(tools, tool), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if reason, ok := r.filteredTools[tool.Name()]; ok {
+		return fmt.Errorf("duplicate MCP tool name %q: already filtered out (%s)", tool.Name(), reason)
+	}
+
+	if !r.addTool(tool) {
+		return fmt.Errorf("MCP tool %q is %w (%s)", tool.Name(), ErrFiltered, r.filteredTools[tool.Name()])
+	}
+
+	if r.mcpServer != nil {
+		r.registerTool(tool)
+	}
+
+	return nil
+}
+
+// RemoveTool removes a tool at runtime, and notifies the connected sessions if the tools list changed.
+func (r *MCPServerRegistry) RemoveTool(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if reason, ok := r.filteredTools[name]; ok {
+		return fmt.Errorf("MCP tool %q is filtered out (%s) and cannot be removed", name, reason)
+	}
+
+	if _, ok := r.tools[name]; !ok {
+		return fmt.Errorf("MCP tool %q is not registered", name)
+	}
+
+	delete(r.tools, name)
+
+	if r.mcpServer != nil {
+		r.mcpServer.DeleteTools(name)
+	}
+
+	return nil
+}
+
+// AddPrompt adds a prompt at runtime, and notifies the connected sessions if the prompts list changed. It returns an
+// error wrapping ErrFiltered if the prompt is filtered out.
+func (r *MCPServerRegistry) AddPrompt(prompt MCPServerPrompt) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prompts := make([]MCPServerPrompt, 0, len(r.prompts)+1)
+	for _, p := range r.prompts {
+		prompts = append(prompts, p)
+	}
+
+	err := ValidateMCPServerRegistrations(nil, append(prompts, prompt), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if reason, ok := r.filteredPrompts[prompt.Name()]; ok {
+		return fmt.Errorf("duplicate MCP prompt name %q: already filtered out (%s)", prompt.Name(), reason)
+	}
+
+	if !r.addPrompt(prompt) {
+		return fmt.Errorf("MCP prompt %q is %w (%s)", prompt.Name(), ErrFiltered, r.filteredPrompts[prompt.Name()])
+	}
+
+	if r.mcpServer != nil {
+		r.registerPrompt(prompt)
+	}
+
+	return nil
+}
+
+// RemovePrompt removes a prompt at runtime, and notifies the connected sessions if the prompts list changed.
+func (r *MCPServerRegistry) RemovePrompt(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if reason, ok := r.filteredPrompts[name]; ok {
+		return fmt.Errorf("MCP prompt %q is filtered out (%s) and cannot be removed", name, reason)
+	}
+
+	if _, ok := r.prompts[name]; !ok {
+		return fmt.Errorf("MCP prompt %q is not registered", name)
+	}
+
+	delete(r.prompts, name)
+
+	if r.mcpServer != nil {
+		r.mcpServer.DeletePrompts(name)
+	}
+
+	return nil
+}
+
+// AddResource adds a resource at runtime, and notifies the connected sessions if the resources list changed. It
+// returns an error wrapping ErrFiltered if the resource is filtered out.
+func (r *MCPServerRegistry) AddResource(resource MCPServerResource) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	resources := make([]MCPServerResource, 0, len(r.resources)+1)
+	for _, res := range r.resources {
+		resources = append(resources, res)
+	}
+
+	err := ValidateMCPServerRegistrations(nil, nil, append(resources, resource), nil)
+	if err != nil {
+		return err
+	}
+
+	if reason, ok := r.filteredResources[resource.Name()]; ok {
+		return fmt.Errorf("duplicate MCP resource name %q: already filtered out (%s)", resource.Name(), reason)
+	}
+
+	if !r.addResource(resource) {
+		return fmt.Errorf("MCP resource %q is %w (%s)", resource.Name(), ErrFiltered, r.filteredResources[resource.Name()])
+	}
+
+	if r.mcpServer != nil {
+		r.registerResource(resource)
+	}
+
+	return nil
+}
+
+// RemoveResource removes a resource at runtime, and notifies the connected sessions if the resources list changed.
+func (r *MCPServerRegistry) RemoveResource(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if reason, ok := r.filteredResources[name]; ok {
+		return fmt.Errorf("MCP resource %q is filtered out (%s) and cannot be removed", name, reason)
+	}
+
+	resource, ok := r.resources[name]
+	if !ok {
+		return fmt.Errorf("MCP resource %q is not registered", name)
+	}
+
+	delete(r.resources, name)
+
+	if r.mcpServer != nil {
+		r.mcpServer.DeleteResources(resource.URI())
+	}
+
+	return nil
+}
+
+// AddResourceTemplate adds a resource template at runtime, and notifies the connected sessions if the resources list
+// changed. It returns an error wrapping ErrFiltered if the resource template is filtered out.
+func (r *MCPServerRegistry) AddResourceTemplate(resourceTemplate MCPServerResourceTemplate) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	resourceTemplates := make([]MCPServerResourceTemplate, 0, len(r.resourceTemplates)+1)
+	for _, rt := range r.resourceTemplates {
+		resourceTemplates = append(resourceTemplates, rt)
+	}
+
+	err := ValidateMCPServerRegistrations(nil, nil, nil, append(resourceTemplates, resourceTemplate))
+	if err != nil {
+		return err
+	}
+
+	if reason, ok := r.filteredResourceTemplates[resourceTemplate.Name()]; ok {
+		return fmt.Errorf("duplicate MCP resource template name %q: already filtered out (%s)", resourceTemplate.Name(), reason)
+	}
+
+	if !r.addResourceTemplate(resourceTemplate) {
+		return fmt.Errorf("MCP resource template %q is %w (%s)", resourceTemplate.Name(), ErrFiltered, r.filteredResourceTemplates[resourceTemplate.Name()])
+	}
+
+	if r.mcpServer != nil {
+		r.registerResourceTemplates()
+	}
+
+	return nil
+}
+
+// RemoveResourceTemplate removes a resource template at runtime, and notifies the connected sessions if the resources list changed.
+func (r *MCPServerRegistry) RemoveResourceTemplate(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if reason, ok := r.filteredResourceTemplates[name]; ok {
+		return fmt.Errorf("MCP resource template %q is filtered out (%s) and cannot be removed", name, reason)
+	}
+
+	if _, ok := r.resourceTemplates[name]; !ok {
+		return fmt.Errorf("MCP resource template %q is not registered", name)
+	}
+
+	delete(r.resourceTemplates, name)
+
+	if r.mcpServer != nil {
+		r.registerResourceTemplates()
+	}
+
+	return nil
+}
+
+// HasTool returns true if a tool is registered for a given name.
+func (r *MCPServerRegistry) HasTool(name string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.tools[name]
+
+	return ok
+}
+
+// HasPrompt returns true if a prompt is registered for a given name.
+func (r *MCPServerRegistry) HasPrompt(name string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.prompts[name]
+
+	return ok
+}
+
+// ResolveResourceName returns the name of the registered resource, or resource template, matching a given URI.
+func (r *MCPServerRegistry) ResolveResourceName(uri string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for name, resource := range r.resources {
+		if resource.URI() == uri {
+			return name, true
 		}
 	}
 
-	if r.config.GetBool("modules.mcp.server.capabilities.resources") {
-		for _, resource := range r.resources {
-			mcpServer.AddResource(
-				mcp.NewResource(resource.URI(), resource.Name(), resource.Options()...),
-				resource.Handle(),
-			)
+	names := make([]string, 0, len(r.resourceTemplates))
+	for name := range r.resourceTemplates {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		template := r.resourceTemplates[name]
+
+		if template.URI() == uri {
+			return name, true
 		}
 
-		for _, resourceTemplate := range r.resourceTemplates {
-			mcpServer.AddResourceTemplate(
-				mcp.NewResourceTemplate(resourceTemplate.URI(), resourceTemplate.Name(), resourceTemplate.Options()...),
-				resourceTemplate.Handle(),
-			)
+		compiled, err := r.uriTemplate(template.URI())
+		if err == nil && compiled.Match(uri) != nil {
+			return name, true
 		}
 	}
+
+	return "", false
 }
 
 func (r *MCPServerRegistry) Info() MCPServerRegistryInfo {
-	toolsInfo := make(map[string]string, len(r.tools))
-	for _, tool := range r.tools {
-		toolsInfo[tool.Name()] = FuncName(tool.Handle())
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	toolsInfo := make(map[string]MCPServerToolInfo, len(r.tools))
+	for name, tool := range r.tools {
+		mcpTool := r.buildTool(tool)
+
+		var inputSchema any = mcpTool.InputSchema
+		if mcpTool.RawInputSchema != nil {
+			inputSchema = mcpTool.RawInputSchema
+		}
+
+		toolsInfo[name] = MCPServerToolInfo{
+			Name:        mcpTool.Name,
+			Description: mcpTool.Description,
+			InputSchema: inputSchema,
+			Annotations: mcpTool.Annotations,
+			Type:        TypeName(tool),
+			Source:      SourceLocation(tool),
+		}
 	}
 
-	promptsInfo := make(map[string]string, len(r.prompts))
-	for _, prompt := range r.prompts {
-		promptsInfo[prompt.Name()] = FuncName(prompt.Handle())
+	promptsInfo := make(map[string]MCPServerPromptInfo, len(r.prompts))
+	for name, prompt := range r.prompts {
+		mcpPrompt := mcp.NewPrompt(prompt.Name(), prompt.Options()...)
+
+		promptsInfo[name] = MCPServerPromptInfo{
+			Name:        mcpPrompt.Name,
+			Description: mcpPrompt.Description,
+			Arguments:   mcpPrompt.Arguments,
+			Type:        TypeName(prompt),
+			Source:      SourceLocation(prompt),
+		}
 	}
 
-	resourcesInfo := make(map[string]string, len(r.resources))
-	for _, resource := range r.resources {
-		resourcesInfo[resource.Name()] = FuncName(resource.Handle())
+	resourcesInfo := make(map[string]MCPServerResourceInfo, len(r.resources))
+	for name, resource := range r.resources {
+		mcpResource := mcp.NewResource(resource.URI(), resource.Name(), resource.Options()...)
+
+		resourcesInfo[name] = MCPServerResourceInfo{
+			Name:        mcpResource.Name,
+			URI:         mcpResource.URI,
+			Description: mcpResource.Description,
+			MIMEType:    mcpResource.MIMEType,
+			Type:        TypeName(resource),
+			Source:      SourceLocation(resource),
+		}
 	}
 
-	resourceTemplatesInfo := make(map[string]string, len(r.resourceTemplates))
-	for _, resourceTemplate := range r.resourceTemplates {
-		resourceTemplatesInfo[resourceTemplate.Name()] = FuncName(resourceTemplate.Handle())
+	resourceTemplatesInfo := make(map[string]MCPServerResourceTemplateInfo, len(r.resourceTemplates))
+	for name, resourceTemplate := range r.resourceTemplates {
+		mcpResourceTemplate := mcp.NewResourceTemplate(resourceTemplate.URI(), resourceTemplate.Name(), resourceTemplate.Options()...)
+
+		resourceTemplatesInfo[name] = MCPServerResourceTemplateInfo{
+			Name:        mcpResourceTemplate.Name,
+			URITemplate: resourceTemplate.URI(),
+			Description: mcpResourceTemplate.Description,
+			MIMEType:    mcpResourceTemplate.MIMEType,
+			Type:        TypeName(resourceTemplate),
+			Source:      SourceLocation(resourceTemplate),
+		}
 	}
 
 	return MCPServerRegistryInfo{
+		ReadOnly: r.config.GetBool("modules.mcp.server.read_only"),
 		Capabilities: struct {
 			Tools     bool
 			Prompts   bool
@@ -159,15 +556,146 @@ func (r *MCPServerRegistry) Info() MCPServerRegistryInfo {
 			Resources: r.config.GetBool("modules.mcp.server.capabilities.resources"),
 		},
 		Registrations: struct {
-			Tools             map[string]string
-			Prompts           map[string]string
-			Resources         map[string]string
-			ResourceTemplates map[string]string
+			Tools             map[string]MCPServerToolInfo
+			Prompts           map[string]MCPServerPromptInfo
+			Resources         map[string]MCPServerResourceInfo
+			ResourceTemplates map[string]MCPServerResourceTemplateInfo
 		}{
 			Tools:             toolsInfo,
 			Prompts:           promptsInfo,
 			Resources:         resourcesInfo,
 			ResourceTemplates: resourceTemplatesInfo,
 		},
+		Filtered: struct {
+			Tools             map[string]string
+			Prompts           map[string]string
+			Resources         map[string]string
+			ResourceTemplates map[string]string
+		}{
+			Tools:             maps.Clone(r.filteredTools),
+			Prompts:           maps.Clone(r.filteredPrompts),
+			Resources:         maps.Clone(r.filteredResources),
+			ResourceTemplates: maps.Clone(r.filteredResourceTemplates),
+		},
+	}
+}
+
+func (r *MCPServerRegistry) addTool(tool MCPServerTool) bool {
+	allowed, reason := r.allow("tools", "tools", tool.Name())
+	if allowed && r.config.GetBool("modules.mcp.server.read_only") && !IsReadOnlyTool(r.buildTool(tool)) {
+		allowed, reason = false, "read only mode, tool not annotated as read-only"
 	}
+
+	if allowed {
+		r.tools[tool.Name()] = tool
+	} else {
+		r.filteredTools[tool.Name()] = reason
+	}
+
+	return allowed
+}
+
+func (r *MCPServerRegistry) addPrompt(prompt MCPServerPrompt) bool {
+	allowed, reason := r.allow("prompts", "prompts", prompt.Name())
+	if allowed {
+		r.prompts[prompt.Name()] = prompt
+	} else {
+		r.filteredPrompts[prompt.Name()] = reason
+	}
+
+	return allowed
+}
+
+func (r *MCPServerRegistry) addResource(resource MCPServerResource) bool {
+	allowed, reason := r.allow("resources", "resources", resource.Name())
+	if allowed {
+		r.resources[resource.Name()] = resource
+	} else {
+		r.filteredResources[resource.Name()] = reason
+	}
+
+	return allowed
+}
+
+func (r *MCPServerRegistry) addResourceTemplate(resourceTemplate MCPServerResourceTemplate) bool {
+	allowed, reason := r.allow("resources", "resource_templates", resourceTemplate.Name())
+	if allowed {
+		r.resourceTemplates[resourceTemplate.Name()] = resourceTemplate
+	} else {
+		r.filteredResourceTemplates[resourceTemplate.Name()] = reason
+	}
+
+	return allowed
+}
+
+func (r *MCPServerRegistry) allow(capability string, key string, name string) (bool, string) {
+	if !r.config.GetBool(fmt.Sprintf("modules.mcp.server.capabilities.%s", capability)) {
+		return false, fmt.Sprintf("%s capability disabled", capability)
+	}
+
+	return NewMCPServerRegistrationFilter(r.config, key).Allow(name)
+}
+
+func (r *MCPServerRegistry) buildTool(tool MCPServerTool) mcp.Tool {
+	mcpTool := mcp.NewTool(tool.Name(), tool.Options()...)
+
+	ApplyToolAnnotationsConfig(r.config, &mcpTool)
+
+	return mcpTool
+}
+
+func (r *MCPServerRegistry) registerTool(tool MCPServerTool) {
+	r.mcpServer.AddTool(
+		r.buildTool(tool),
+		withPanicSpan(tool.Name(), applyToolMiddlewares(tool.Handle(), r.toolMiddlewares)),
+	)
+}
+
+func (r *MCPServerRegistry) registerPrompt(prompt MCPServerPrompt) {
+	r.mcpServer.AddPrompt(
+		mcp.NewPrompt(prompt.Name(), prompt.Options()...),
+		withPanicSpan(prompt.Name(), applyPromptMiddlewares(prompt.Handle(), r.promptMiddlewares)),
+	)
+}
+
+func (r *MCPServerRegistry) registerResource(resource MCPServerResource) {
+	r.mcpServer.AddResource(
+		mcp.NewResource(resource.URI(), resource.Name(), resource.Options()...),
+		withPanicSpan(resource.Name(), applyResourceMiddlewares(resource.Handle(), r.resourceMiddlewares)),
+	)
+}
+
+// registerResourceTemplates replaces all resource templates on the MCP server, since it does not offer removal.
+func (r *MCPServerRegistry) registerResourceTemplates() {
+	resourceTemplates := make([]server.ServerResourceTemplate, 0, len(r.resourceTemplates))
+
+	for _, resourceTemplate := range r.resourceTemplates {
+		resourceTemplates = append(resourceTemplates, server.ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate(resourceTemplate.URI(), resourceTemplate.Name(), resourceTemplate.Options()...),
+			Handler: server.ResourceTemplateHandlerFunc(
+				withPanicSpan(
+					resourceTemplate.Name(),
+					applyResourceMiddlewares(server.ResourceHandlerFunc(resourceTemplate.Handle()), r.resourceMiddlewares),
+				),
+			),
+		})
+	}
+
+	r.mcpServer.SetResourceTemplates(resourceTemplates...)
+}
+
+func (r *MCPServerRegistry) uriTemplate(uri string) (*uritemplate.Template, error) {
+	if compiled, ok := r.uriTemplates.Load(uri); ok {
+		//nolint:forcetypeassert
+		return compiled.(*uritemplate.Template), nil
+	}
+
+	compiled, err := uritemplate.New(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	r.uriTemplates.Store(uri, compiled)
+
+	return compiled, nil
 }
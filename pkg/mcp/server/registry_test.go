@@ -0,0 +1,204 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filteringConfig = `
+modules:
+  mcp:
+    server:
+      capabilities:
+        tools: true
+        prompts: true
+        resources: true
+      tools:
+        exclude:
+          - "*-delete"
+      prompts:
+        exclude:
+          - "secret-*"
+      resources:
+        exclude:
+          - "secret-*"
+      resource_templates:
+        exclude:
+          - "secret-*"
+`
+
+func newTestRegistry(t *testing.T) (*yokaimcpserver.MCPServerRegistry, *server.MCPServer) {
+	t.Helper()
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		mcptest.NewConfig(t, filteringConfig),
+		[]yokaimcpserver.MCPServerTool{newTestTool("book-list"), newTestTool("book-delete")},
+		[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}, &testPrompt{name: "secret-prompt"}},
+		[]yokaimcpserver.MCPServerResource{
+			&testResource{name: "weather", uri: "weather://current"},
+			&testResource{name: "secret-resource", uri: "secret://resource"},
+		},
+		[]yokaimcpserver.MCPServerResourceTemplate{
+			&testResourceTemplate{name: "book", uri: "books://{id}"},
+			&testResourceTemplate{name: "secret-template", uri: "secrets://{id}"},
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	registry.Register(mcpServer)
+
+	return registry, mcpServer
+}
+
+func TestMCPServerRegistryAddAndRemove(t *testing.T) {
+	t.Parallel()
+
+	registry, mcpServer := newTestRegistry(t)
+
+	require.NoError(t, registry.AddTool(newTestTool("book-get")))
+	assert.NotNil(t, mcpServer.GetTool("book-get"))
+	assert.Contains(t, registry.Info().Registrations.Tools, "book-get")
+
+	require.NoError(t, registry.RemoveTool("book-get"))
+	assert.Nil(t, mcpServer.GetTool("book-get"))
+	assert.NotContains(t, registry.Info().Registrations.Tools, "book-get")
+
+	require.NoError(t, registry.AddPrompt(&testPrompt{name: "farewell"}))
+	require.NoError(t, registry.RemovePrompt("farewell"))
+
+	require.NoError(t, registry.AddResource(&testResource{name: "forecast", uri: "weather://forecast"}))
+	require.NoError(t, registry.RemoveResource("forecast"))
+
+	require.NoError(t, registry.AddResourceTemplate(&testResourceTemplate{name: "author", uri: "authors://{id}"}))
+	require.NoError(t, registry.RemoveResourceTemplate("author"))
+
+	assert.ErrorContains(t, registry.RemoveTool("unknown"), `MCP tool "unknown" is not registered`)
+	assert.ErrorContains(t, registry.RemovePrompt("unknown"), `MCP prompt "unknown" is not registered`)
+	assert.ErrorContains(t, registry.RemoveResource("unknown"), `MCP resource "unknown" is not registered`)
+	assert.ErrorContains(t, registry.RemoveResourceTemplate("unknown"), `MCP resource template "unknown" is not registered`)
+}
+
+func TestMCPServerRegistryAddFilteredOut(t *testing.T) {
+	t.Parallel()
+
+	registry, mcpServer := newTestRegistry(t)
+
+	err := registry.AddTool(newTestTool("author-delete"))
+	assert.ErrorIs(t, err, yokaimcpserver.ErrFiltered)
+	assert.EqualError(t, err, `MCP tool "author-delete" is filtered out (matching exclude pattern "*-delete")`)
+	assert.Nil(t, mcpServer.GetTool("author-delete"))
+	assert.Equal(t, `matching exclude pattern "*-delete"`, registry.Info().Filtered.Tools["author-delete"])
+
+	err = registry.AddPrompt(&testPrompt{name: "secret-farewell"})
+	assert.ErrorIs(t, err, yokaimcpserver.ErrFiltered)
+	assert.Contains(t, registry.Info().Filtered.Prompts, "secret-farewell")
+
+	err = registry.AddResource(&testResource{name: "secret-forecast", uri: "secret://forecast"})
+	assert.ErrorIs(t, err, yokaimcpserver.ErrFiltered)
+	assert.Contains(t, registry.Info().Filtered.Resources, "secret-forecast")
+
+	err = registry.AddResourceTemplate(&testResourceTemplate{name: "secret-author", uri: "secret-authors://{id}"})
+	assert.ErrorIs(t, err, yokaimcpserver.ErrFiltered)
+	assert.Contains(t, registry.Info().Filtered.ResourceTemplates, "secret-author")
+}
+
+func TestMCPServerRegistryAddDuplicate(t *testing.T) {
+	t.Parallel()
+
+	registry, _ := newTestRegistry(t)
+
+	assert.ErrorContains(t, registry.AddTool(newTestTool("book-list")), `duplicate MCP tool name "book-list"`)
+	assert.ErrorContains(t, registry.AddTool(newTestTool("book-delete")), `duplicate MCP tool name "book-delete": already filtered out`)
+
+	assert.ErrorContains(t, registry.AddPrompt(&testPrompt{name: "greet"}), `duplicate MCP prompt name "greet"`)
+	assert.ErrorContains(t, registry.AddPrompt(&testPrompt{name: "secret-prompt"}), `duplicate MCP prompt name "secret-prompt": already filtered out`)
+
+	assert.ErrorContains(
+		t,
+		registry.AddResource(&testResource{name: "weather", uri: "weather://other"}),
+		`duplicate MCP resource name "weather"`,
+	)
+	assert.ErrorContains(
+		t,
+		registry.AddResource(&testResource{name: "secret-resource", uri: "secret://other"}),
+		`duplicate MCP resource name "secret-resource": already filtered out`,
+	)
+
+	assert.ErrorContains(
+		t,
+		registry.AddResourceTemplate(&testResourceTemplate{name: "book", uri: "other://{id}"}),
+		`duplicate MCP resource template name "book"`,
+	)
+	assert.ErrorContains(
+		t,
+		registry.AddResourceTemplate(&testResourceTemplate{name: "secret-template", uri: "other://{id}"}),
+		`duplicate MCP resource template name "secret-template": already filtered out`,
+	)
+}
+
+func TestMCPServerRegistryRemoveFilteredOut(t *testing.T) {
+	t.Parallel()
+
+	registry, _ := newTestRegistry(t)
+
+	assert.ErrorContains(t, registry.RemoveTool("book-delete"), `MCP tool "book-delete" is filtered out`)
+	assert.ErrorContains(t, registry.RemovePrompt("secret-prompt"), `MCP prompt "secret-prompt" is filtered out`)
+	assert.ErrorContains(t, registry.RemoveResource("secret-resource"), `MCP resource "secret-resource" is filtered out`)
+	assert.ErrorContains(t, registry.RemoveResourceTemplate("secret-template"), `MCP resource template "secret-template" is filtered out`)
+
+	info := registry.Info()
+	assert.Contains(t, info.Filtered.Tools, "book-delete")
+	assert.Contains(t, info.Filtered.Prompts, "secret-prompt")
+	assert.Contains(t, info.Filtered.Resources, "secret-resource")
+	assert.Contains(t, info.Filtered.ResourceTemplates, "secret-template")
+}
+
+func TestMCPServerRegistryInfo(t *testing.T) {
+	t.Parallel()
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		mcptest.NewConfig(t, allCapabilitiesConfig),
+		[]yokaimcpserver.MCPServerTool{
+			newTestTool("book-list", mcp.WithDescription("List books"), mcp.WithString("genre", mcp.Required())),
+			newTestTypedTool(t),
+		},
+		[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}},
+		[]yokaimcpserver.MCPServerResource{&testResource{name: "weather", uri: "weather://current"}},
+		[]yokaimcpserver.MCPServerResourceTemplate{&testResourceTemplate{name: "book", uri: "books://{id}"}},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	info := registry.Info()
+
+	assert.True(t, info.Capabilities.Tools)
+	assert.True(t, info.Capabilities.Prompts)
+	assert.True(t, info.Capabilities.Resources)
+	assert.False(t, info.ReadOnly)
+
+	toolInfo := info.Registrations.Tools["book-list"]
+	assert.Equal(t, "List books", toolInfo.Description)
+	assert.Equal(t, []string{"genre"}, toolInfo.InputSchema.(mcp.ToolInputSchema).Required)
+	assert.Equal(t, "*github.com/ekkinox/yokai-mcp/pkg/mcp/server_test.testTool", toolInfo.Type)
+	assert.Contains(t, toolInfo.Source, "helper_test.go:")
+
+	typedToolInfo := info.Registrations.Tools["typed"]
+	assert.Equal(t, "typed tool", typedToolInfo.Description)
+	assert.Contains(t, typedToolInfo.Source, "typed_test.go:")
+
+	assert.Equal(t, "greet", info.Registrations.Prompts["greet"].Name)
+	assert.Equal(t, "weather://current", info.Registrations.Resources["weather"].URI)
+	assert.Equal(t, "books://{id}", info.Registrations.ResourceTemplates["book"].URITemplate)
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MCPServerSchemaField describes a struct field used as MCP tool input, as derived from its struct tags:
+//   - json: name of the argument (fields with "-" are skipped)
+//   - description: description of the argument
+//   - required: "true" if the argument is required
+//   - enum: comma separated list of allowed values
+//   - default: default value of the argument
+type MCPServerSchemaField struct {
+	Index       []int
+	Name        string
+	Type        reflect.Type
+	Description string
+	Required    bool
+	Enum        []string
+	Default     string
+}
+
+// SchemaFields returns the MCPServerSchemaField list of a given struct type.
+func SchemaFields(t reflect.Type) []MCPServerSchemaField {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []MCPServerSchemaField
+
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		field := MCPServerSchemaField{
+			Index:       f.Index,
+			Name:        name,
+			Type:        f.Type,
+			Description: f.Tag.Get("description"),
+			Default:     f.Tag.Get("default"),
+		}
+
+		if required, err := strconv.ParseBool(f.Tag.Get("required")); err == nil {
+			field.Required = required
+		}
+
+		if enum := f.Tag.Get("enum"); enum != "" {
+			field.Enum = strings.Split(enum, ",")
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Schema returns the JSON schema properties and required fields of a given struct type, or an error if the type
+// is recursive or contains unsupported field types.
+func Schema(t reflect.Type) (map[string]any, []string, error) {
+	return schema(t, map[reflect.Type]struct{}{indirectType(t): {}})
+}
+
+func schema(t reflect.Type, visited map[reflect.Type]struct{}) (map[string]any, []string, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, field := range SchemaFields(t) {
+		property, err := schemaProperty(field.Type, visited)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid field %q: %w", field.Name, err)
+		}
+
+		if field.Description != "" {
+			property["description"] = field.Description
+		}
+
+		if len(field.Enum) > 0 {
+			enum := make([]any, 0, len(field.Enum))
+			for _, e := range field.Enum {
+				v, err := parseValue(field.Type, e)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid enum value %q for field %q: %w", e, field.Name, err)
+				}
+
+				enum = append(enum, v)
+			}
+
+			property["enum"] = enum
+		}
+
+		if field.Default != "" {
+			v, err := parseValue(field.Type, field.Default)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid default value %q for field %q: %w", field.Default, field.Name, err)
+			}
+
+			property["default"] = v
+		}
+
+		if field.Required {
+			required = append(required, field.Name)
+		}
+
+		properties[field.Name] = property
+	}
+
+	return properties, required, nil
+}
+
+func schemaProperty(t reflect.Type, visited map[reflect.Type]struct{}) (map[string]any, error) {
+	t = indirectType(t)
+
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaProperty(t.Elem(), visited)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		return map[string]any{"type": "object"}, nil
+	case reflect.Struct:
+		if _, ok := visited[t]; ok {
+			return nil, fmt.Errorf("recursive type %s", t.String())
+		}
+
+		visited[t] = struct{}{}
+		defer delete(visited, t)
+
+		properties, required, err := schema(t, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		property := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			property["required"] = required
+		}
+
+		return property, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t.String())
+	}
+}
+
+// parseValue converts a given tag string value to a value of the given type.
+func parseValue(t reflect.Type, value string) (any, error) {
+	t = indirectType(t)
+
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
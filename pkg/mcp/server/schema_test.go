@@ -0,0 +1,99 @@
+package server_test
+
+import (
+	"reflect"
+	"testing"
+
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestAddress struct {
+	City    string `json:"city" required:"true"`
+	Country string `json:"country" enum:"fr,uk" default:"fr"`
+}
+
+type schemaTestInput struct {
+	Title     string              `json:"title" required:"true" description:"Title of the book"`
+	Pages     int                 `json:"pages" default:"100"`
+	Genre     string              `json:"genre" enum:"horror,romance"`
+	Tags      []string            `json:"tags"`
+	Address   *schemaTestAddress  `json:"address"`
+	Addresses []schemaTestAddress `json:"addresses"`
+	Ignored   string              `json:"-"`
+	private   string
+}
+
+type schemaTestRecursive struct {
+	Name     string                 `json:"name"`
+	Children []*schemaTestRecursive `json:"children"`
+}
+
+type schemaTestIndirectRecursive struct {
+	Child *schemaTestIndirectChild `json:"child"`
+}
+
+type schemaTestIndirectChild struct {
+	Parent *schemaTestIndirectRecursive `json:"parent"`
+}
+
+type schemaTestSiblings struct {
+	From schemaTestAddress `json:"from"`
+	To   schemaTestAddress `json:"to"`
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	properties, required, err := yokaimcpserver.Schema(reflect.TypeFor[schemaTestInput]())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"title"}, required)
+	assert.Len(t, properties, 6)
+
+	assert.Equal(t, map[string]any{"type": "string", "description": "Title of the book"}, properties["title"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": int64(100)}, properties["pages"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"horror", "romance"}}, properties["genre"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, properties["tags"])
+
+	address := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city":    map[string]any{"type": "string"},
+			"country": map[string]any{"type": "string", "enum": []any{"fr", "uk"}, "default": "fr"},
+		},
+		"required": []string{"city"},
+	}
+	assert.Equal(t, address, properties["address"])
+	assert.Equal(t, map[string]any{"type": "array", "items": address}, properties["addresses"])
+}
+
+func TestSchemaWithRepeatedNonRecursiveType(t *testing.T) {
+	t.Parallel()
+
+	properties, _, err := yokaimcpserver.Schema(reflect.TypeFor[schemaTestSiblings]())
+	require.NoError(t, err)
+
+	assert.Contains(t, properties, "from")
+	assert.Contains(t, properties, "to")
+}
+
+func TestSchemaWithRecursiveType(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := yokaimcpserver.Schema(reflect.TypeFor[schemaTestRecursive]())
+	assert.ErrorContains(t, err, `invalid field "children": recursive type server_test.schemaTestRecursive`)
+
+	_, _, err = yokaimcpserver.Schema(reflect.TypeFor[schemaTestIndirectRecursive]())
+	assert.ErrorContains(t, err, "recursive type server_test.schemaTestIndirectRecursive")
+}
+
+func TestSchemaWithUnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := yokaimcpserver.Schema(reflect.TypeFor[struct {
+		Callback func() `json:"callback"`
+	}]())
+	assert.ErrorContains(t, err, `invalid field "callback": unsupported type func()`)
+}
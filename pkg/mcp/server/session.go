@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// UnknownClientName is the client name used until the MCP session is initialized.
+const UnknownClientName = "unknown"
+
+// OtherClientName is the metric client label value used for clients not allowed (see MCPServerHooksSettings.MetricClient).
+const OtherClientName = "other"
+
+// MCPServerSessionStats holds the statistics of a MCP session.
+type MCPServerSessionStats struct {
+	mutex       sync.Mutex
+	transport   string
+	clientName  string
+	startTime   time.Time
+	requests    int
+	errors      int
+	initialized bool
+}
+
+// NewMCPServerSessionStats returns a new MCPServerSessionStats, for a given transport.
+func NewMCPServerSessionStats(transport string) *MCPServerSessionStats {
+	return &MCPServerSessionStats{
+		transport:  transport,
+		clientName: UnknownClientName,
+		startTime:  time.Now(),
+	}
+}
+
+// Initialize marks the session as initialized by a given client, and returns false if it was already initialized.
+func (s *MCPServerSessionStats) Initialize(clientName string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.initialized {
+		return false
+	}
+
+	if clientName != "" {
+		s.clientName = clientName
+	}
+
+	s.initialized = true
+
+	return true
+}
+
+// Record records a processed request, and if it failed.
+func (s *MCPServerSessionStats) Record(failed bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests++
+
+	if failed {
+		s.errors++
+	}
+}
+
+// Transport returns the session transport.
+func (s *MCPServerSessionStats) Transport() string {
+	return s.transport
+}
+
+// ClientName returns the session client name.
+func (s *MCPServerSessionStats) ClientName() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.clientName
+}
+
+// Initialized returns true if the session was initialized.
+func (s *MCPServerSessionStats) Initialized() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.initialized
+}
+
+// Requests returns the number of processed requests.
+func (s *MCPServerSessionStats) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests
+}
+
+// Errors returns the number of failed requests.
+func (s *MCPServerSessionStats) Errors() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.errors
+}
+
+// Duration returns the session duration.
+func (s *MCPServerSessionStats) Duration() time.Duration {
+	return time.Since(s.startTime)
+}
+
+// SessionTransport returns the transport name of a given server.ClientSession.
+//
+// Sessions can provide a Transport() string method, otherwise the transport is deduced from the session type.
+func SessionTransport(session server.ClientSession) string {
+	if t, ok := session.(interface{ Transport() string }); ok {
+		return t.Transport()
+	}
+
+	typeName := strings.ToLower(reflect.TypeOf(session).String())
+
+	switch {
+	case strings.Contains(typeName, "streamablehttp"):
+		return "streamable_http"
+	case strings.Contains(typeName, "sse"):
+		return "sse"
+	case strings.Contains(typeName, "stdio"):
+		return "stdio"
+	default:
+		return "unknown"
+	}
+}
+
+// SessionID returns the ID of the MCP session found in a given context, or an empty string.
+func SessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+
+	return ""
+}
@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServerSessionStats(t *testing.T) {
+	t.Parallel()
+
+	stats := yokaimcpserver.NewMCPServerSessionStats("sse")
+
+	assert.Equal(t, "sse", stats.Transport())
+	assert.Equal(t, yokaimcpserver.UnknownClientName, stats.ClientName())
+	assert.False(t, stats.Initialized())
+
+	assert.True(t, stats.Initialize("cursor"))
+	assert.False(t, stats.Initialize("other-client"))
+	assert.Equal(t, "cursor", stats.ClientName())
+	assert.True(t, stats.Initialized())
+
+	stats.Record(false)
+	stats.Record(true)
+	assert.Equal(t, 2, stats.Requests())
+	assert.Equal(t, 1, stats.Errors())
+	assert.Positive(t, stats.Duration())
+}
+
+type unnamedSession struct {
+	server.ClientSession
+}
+
+func TestSessionTransport(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "test", yokaimcpserver.SessionTransport(newTestSession("id")))
+	assert.Equal(t, "unknown", yokaimcpserver.SessionTransport(&unnamedSession{}))
+}
+
+func TestSessionID(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	assert.Equal(t, "", yokaimcpserver.SessionID(context.Background()))
+	assert.Equal(t, "id", yokaimcpserver.SessionID(mcpServer.WithContext(context.Background(), newTestSession("id"))))
+}
+
+func TestMCPServerHooksSettingsMetricClient(t *testing.T) {
+	t.Parallel()
+
+	settings := yokaimcpserver.NewMCPServerHooksSettings(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      metrics:
+        clients:
+          allow:
+            - "cursor"
+`))
+
+	assert.Equal(t, "cursor", settings.MetricClient("cursor"))
+	assert.Equal(t, yokaimcpserver.UnknownClientName, settings.MetricClient(yokaimcpserver.UnknownClientName))
+	assert.Equal(t, yokaimcpserver.OtherClientName, settings.MetricClient("Cursor"))
+	assert.Equal(t, yokaimcpserver.OtherClientName, settings.MetricClient("random-client-123"))
+}
+
+func TestSessionMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := newTestHooksServer(t, `
+modules:
+  mcp:
+    server:
+      metrics:
+        collect:
+          enabled: true
+        clients:
+          allow:
+            - "cursor"
+`)
+
+	s.connect(t, newTestSession("s1"), "cursor")
+	s.connect(t, newTestSession("s2"), "random-client-123")
+	s.connect(t, newTestSession("s3"), "random-client-456")
+
+	require.NoError(t, s.mcpServer.RegisterSession(context.Background(), newTestSession("s4")))
+
+	expected := `
+# HELP mcp_server_active_sessions Number of active MCP sessions
+# TYPE mcp_server_active_sessions gauge
+mcp_server_active_sessions{client="cursor",transport="test"} 1
+mcp_server_active_sessions{client="other",transport="test"} 2
+mcp_server_active_sessions{client="unknown",transport="test"} 1
+# HELP mcp_server_sessions_total Number of MCP sessions
+# TYPE mcp_server_sessions_total counter
+mcp_server_sessions_total{client="cursor",transport="test"} 1
+mcp_server_sessions_total{client="other",transport="test"} 2
+`
+	err := testutil.GatherAndCompare(
+		s.prometheus,
+		strings.NewReader(expected),
+		"mcp_server_active_sessions",
+		"mcp_server_sessions_total",
+	)
+	assert.NoError(t, err)
+
+	s.mcpServer.UnregisterSession(context.Background(), "s2")
+	s.mcpServer.UnregisterSession(context.Background(), "s4")
+
+	expected = `
+# HELP mcp_server_active_sessions Number of active MCP sessions
+# TYPE mcp_server_active_sessions gauge
+mcp_server_active_sessions{client="cursor",transport="test"} 1
+mcp_server_active_sessions{client="other",transport="test"} 1
+mcp_server_active_sessions{client="unknown",transport="test"} 0
+# HELP mcp_server_sessions_total Number of MCP sessions
+# TYPE mcp_server_sessions_total counter
+mcp_server_sessions_total{client="cursor",transport="test"} 1
+mcp_server_sessions_total{client="other",transport="test"} 2
+mcp_server_sessions_total{client="unknown",transport="test"} 1
+`
+	err = testutil.GatherAndCompare(
+		s.prometheus,
+		strings.NewReader(expected),
+		"mcp_server_active_sessions",
+		"mcp_server_sessions_total",
+	)
+	assert.NoError(t, err)
+}
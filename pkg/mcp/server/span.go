@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ankorstore/yokai/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// withPanicSpan records panics of a given handler as their own short span, before propagating them to the recovery.
+func withPanicSpan[Req any, Res any](
+	name string,
+	handler func(context.Context, Req) (Res, error),
+) func(context.Context, Req) (Res, error) {
+	return func(ctx context.Context, request Req) (Res, error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err := fmt.Errorf("panic in %s handler: %v", name, rec)
+
+				_, span := trace.CtxTracer(ctx).Start(
+					ctx,
+					fmt.Sprintf("MCP panic %s", name),
+					oteltrace.WithSpanKind(oteltrace.SpanKindInternal),
+					oteltrace.WithAttributes(attribute.String("system", "mcpserver")),
+				)
+				span.RecordError(err, oteltrace.WithStackTrace(true))
+				span.SetStatus(codes.Error, err.Error())
+				span.End()
+
+				panic(rec)
+			}
+		}()
+
+		return handler(ctx, request)
+	}
+}
@@ -5,12 +5,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ankorstore/yokai/config"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/log"
-	"github.com/ankorstore/yokai/trace"
 	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
 	"github.com/mark3labs/mcp-go/server"
-	"go.opentelemetry.io/otel/attribute"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
@@ -22,31 +21,26 @@ type MCPSSEServerContextHandler interface {
 
 type DefaultMCPSSEServerContextHandler struct {
 	generator      uuid.UuidGenerator
-	tracerProvider oteltrace.TracerProvider
-	logger         *log.Logger
+	handler        *yokaimcpservercontext.TransportContextHandler
+	maxMessageSize int64
 }
 
 func NewDefaultMCPSSEServerContextHandler(
+	config *config.Config,
 	generator uuid.UuidGenerator,
 	tracerProvider oteltrace.TracerProvider,
 	logger *log.Logger,
 ) *DefaultMCPSSEServerContextHandler {
 	return &DefaultMCPSSEServerContextHandler{
 		generator:      generator,
-		tracerProvider: tracerProvider,
-		logger:         logger,
+		handler:        yokaimcpservercontext.NewTransportContextHandler(config, tracerProvider, logger),
+		maxMessageSize: MaxMessageSize(config),
 	}
 }
 
 func (h *DefaultMCPSSEServerContextHandler) Handle() server.SSEContextFunc {
 	return func(ctx context.Context, r *http.Request) context.Context {
-		// start time propagation
-		ctx = yokaimcpservercontext.WithStartTime(ctx, time.Now())
-
-		// sessionId propagation
-		sID := r.URL.Query().Get("sessionId")
-
-		ctx = yokaimcpservercontext.WithSessionID(ctx, sID)
+		start := time.Now()
 
 		// requestId propagation
 		rID := r.Header.Get("X-Request-Id")
@@ -56,35 +50,35 @@ func (h *DefaultMCPSSEServerContextHandler) Handle() server.SSEContextFunc {
 			r.Header.Set("X-Request-Id", rID)
 		}
 
-		ctx = yokaimcpservercontext.WithRequestID(ctx, rID)
-
-		// tracer propagation
-		ctx = trace.WithContext(ctx, h.tracerProvider)
+		// client certificate propagation: only once verified against the client CA, since a presented certificate
+		// can be self-signed with any subject
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			ctx = yokaimcpservercontext.WithClientCertificateSubject(ctx, r.TLS.VerifiedChains[0][0].Subject.String())
+		}
 
-		ctx, span := trace.CtxTracer(ctx).Start(
-			ctx,
-			"MCP",
-			oteltrace.WithNewRoot(),
-			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
-			oteltrace.WithAttributes(
-				attribute.String("system", "mcpserver"),
-				attribute.String("mcp.transport", "sse"),
-				attribute.String("mcp.sessionID", sID),
-				attribute.String("mcp.requestID", rID),
-			),
-		)
+		// trace context propagation, from headers (unless already done by the HTTP server when mounted) then from
+		// the message _meta (which takes precedence)
+		if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+			ctx = yokaimcpservercontext.WithHeaderTraceContext(ctx, r.Header)
+		}
 
-		ctx = yokaimcpservercontext.WithRootSpan(ctx, span)
+		method, isRequest := "", false
 
-		// logger propagation
-		logger := h.logger.
-			With().
-			Str("system", "mcpserver").
-			Str("mcpTransport", "sse").
-			Str("mcpSessionID", sID).
-			Str("mcpRequestID", rID).
-			Logger()
+		if r.Body != nil {
+			// oversized bodies are rejected by the server beforehand, the limit only bounds the read here
+			body, err := yokaimcpservercontext.ReadMessage(nil, r, h.maxMessageSize)
+			if err == nil {
+				ctx = yokaimcpservercontext.WithMessageTraceContext(ctx, body)
+				method, isRequest = yokaimcpservercontext.MessageKind(body)
+			}
+		}
 
-		return logger.WithContext(ctx)
+		return h.handler.Handle(ctx, start, yokaimcpservercontext.TransportMessage{
+			Transport: "sse",
+			SessionID: r.URL.Query().Get("sessionId"),
+			RequestID: rID,
+			Method:    method,
+			IsRequest: isRequest,
+		})
 	}
 }
@@ -0,0 +1,93 @@
+package sse_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDefaultMCPSSEServerContextHandlerClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	ca := newTestCertificate(t, "test-ca", true, nil)
+	caPath, _ := ca.write(t, dir, "ca")
+	certPath, keyPath := newTestCertificate(t, "localhost", false, ca).write(t, dir, "server")
+
+	logger, err := log.NewDefaultLoggerFactory().Create()
+	require.NoError(t, err)
+
+	contextHandler := sse.NewDefaultMCPSSEServerContextHandler(
+		mcptest.NewConfig(t, "app:\n  name: test\n"),
+		uuid.NewDefaultUuidGenerator(),
+		noop.NewTracerProvider(),
+		logger,
+	).Handle()
+
+	tests := []struct {
+		name       string
+		clientAuth string
+		clientCert *testCertificate
+		expected   string
+	}{
+		{"verified certificate", sse.ClientAuthVerifyIfGiven, newTestCertificate(t, "alice", false, ca), "CN=alice"},
+		{"unverified certificate", sse.ClientAuthRequest, newTestCertificate(t, "mallory", false, nil), ""},
+		{"no certificate", sse.ClientAuthVerifyIfGiven, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tlsConfig, err := sse.NewTLSConfig(sse.MCPSSEServerTLSConfig{
+				Cert:       certPath,
+				Key:        keyPath,
+				ClientCA:   caPath,
+				ClientAuth: tt.clientAuth,
+			})
+			require.NoError(t, err)
+
+			testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := contextHandler(r.Context(), r)
+
+				_, _ = w.Write([]byte(yokaimcpservercontext.CtxClientCertificateSubject(ctx)))
+			}))
+			testServer.TLS = tlsConfig
+			testServer.StartTLS()
+			defer testServer.Close()
+
+			rootCAs := x509.NewCertPool()
+			rootCAs.AddCert(ca.cert)
+
+			clientTLSConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+			if tt.clientCert != nil {
+				clientTLSConfig.Certificates = []tls.Certificate{tt.clientCert.tlsCertificate()}
+			}
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSConfig}}
+
+			resp, err := client.Post(testServer.URL+"/message?sessionId=s1", "application/json", strings.NewReader(`{}`))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+}
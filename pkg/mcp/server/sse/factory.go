@@ -14,6 +14,7 @@ const (
 	DefaultSSEEndpoint       = "/sse"
 	DefaultMessageEndpoint   = "/message"
 	DefaultKeepAliveInterval = 10 * time.Second
+	DefaultMaxMessageSize    = 1 << 20
 )
 
 var _ MCPSSEServerFactory = (*DefaultMCPSSEServerFactory)(nil)
@@ -66,6 +67,24 @@ func (f *DefaultMCPSSEServerFactory) Create(mcpServer *server.MCPServer, options
 		keepAliveInterval = time.Duration(keepAliveIntervalConfig) * time.Second
 	}
 
+	mount := f.config.GetBool("modules.mcp.server.transport.sse.mount")
+
+	maxMessageSize := MaxMessageSize(f.config)
+
+	tlsMinVersion := f.config.GetString("modules.mcp.server.transport.sse.tls.min_version")
+	if tlsMinVersion == "" {
+		tlsMinVersion = DefaultTLSMinVersion
+	}
+
+	tlsConfig := MCPSSEServerTLSConfig{
+		Enabled:    f.config.GetBool("modules.mcp.server.transport.sse.tls.enabled"),
+		Cert:       f.config.GetString("modules.mcp.server.transport.sse.tls.cert"),
+		Key:        f.config.GetString("modules.mcp.server.transport.sse.tls.key"),
+		ClientCA:   f.config.GetString("modules.mcp.server.transport.sse.tls.client_ca"),
+		ClientAuth: f.config.GetString("modules.mcp.server.transport.sse.tls.client_auth"),
+		MinVersion: tlsMinVersion,
+	}
+
 	srvConfig := MCPSSEServerConfig{
 		Address:           addr,
 		BaseURL:           baseURL,
@@ -74,11 +93,14 @@ func (f *DefaultMCPSSEServerFactory) Create(mcpServer *server.MCPServer, options
 		MessageEndpoint:   messageEndpoint,
 		KeepAlive:         keepAlive,
 		KeepAliveInterval: keepAliveInterval,
+		Mount:             mount,
+		MaxMessageSize:    maxMessageSize,
+		TLS:               tlsConfig,
 	}
 
 	srvOptions := []server.SSEOption{
 		server.WithBaseURL(srvConfig.BaseURL),
-		server.WithBasePath(srvConfig.BasePath),
+		server.WithStaticBasePath(srvConfig.BasePath),
 		server.WithSSEEndpoint(srvConfig.SSEEndpoint),
 		server.WithMessageEndpoint(srvConfig.MessageEndpoint),
 	}
@@ -91,3 +113,13 @@ func (f *DefaultMCPSSEServerFactory) Create(mcpServer *server.MCPServer, options
 
 	return NewMCPSSEServer(mcpServer, srvConfig, srvOptions...)
 }
+
+// MaxMessageSize returns the max size in bytes of the message requests bodies, configured in
+// modules.mcp.server.transport.sse.max_message_size, or DefaultMaxMessageSize.
+func MaxMessageSize(config *config.Config) int64 {
+	if maxMessageSize := config.GetInt64("modules.mcp.server.transport.sse.max_message_size"); maxMessageSize != 0 {
+		return maxMessageSize
+	}
+
+	return DefaultMaxMessageSize
+}
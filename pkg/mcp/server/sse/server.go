@@ -2,9 +2,16 @@ package sse
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"sync"
 	"time"
 
+	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/log"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/labstack/echo/v4"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -16,19 +23,44 @@ type MCPSSEServerConfig struct {
 	MessageEndpoint   string
 	KeepAlive         bool
 	KeepAliveInterval time.Duration
+	Mount             bool
+	MaxMessageSize    int64
+	TLS               MCPSSEServerTLSConfig
 }
 
 type MCPSSEServer struct {
-	server  *server.SSEServer
-	config  MCPSSEServerConfig
-	running bool
+	server        *server.SSEServer
+	httpServer    *http.Server
+	authenticator auth.MCPAuthenticator
+	generator     uuid.UuidGenerator
+	principals    sync.Map
+	config        MCPSSEServerConfig
+	running       bool
+	mounted       bool
 }
 
+// sessionIDHolderKey is the context key of the ID of the session opened by a SSE request, once generated.
+type sessionIDHolderKey struct{}
+
 func NewMCPSSEServer(mcpServer *server.MCPServer, config MCPSSEServerConfig, opts ...server.SSEOption) *MCPSSEServer {
-	return &MCPSSEServer{
-		server: server.NewSSEServer(mcpServer, opts...),
-		config: config,
+	httpServer := &http.Server{
+		Addr: config.Address,
+	}
+
+	s := &MCPSSEServer{
+		httpServer: httpServer,
+		generator:  uuid.NewDefaultUuidGenerator(),
+		config:     config,
 	}
+
+	s.server = server.NewSSEServer(
+		mcpServer,
+		append([]server.SSEOption{server.WithHTTPServer(httpServer), server.WithSessionIDGenerator(s.sessionID)}, opts...)...,
+	)
+
+	httpServer.Handler = s.handler(s.server)
+
+	return s
 }
 
 func (s *MCPSSEServer) Server() *server.SSEServer {
@@ -42,6 +74,10 @@ func (s *MCPSSEServer) Config() MCPSSEServerConfig {
 func (s *MCPSSEServer) Start(ctx context.Context) error {
 	logger := log.CtxLogger(ctx)
 
+	if s.config.TLS.Enabled {
+		return s.startTLS(ctx)
+	}
+
 	logger.Info().Msgf("starting MCP SSE server on %s", s.config.Address)
 
 	s.running = true
@@ -56,6 +92,35 @@ func (s *MCPSSEServer) Start(ctx context.Context) error {
 	return err
 }
 
+func (s *MCPSSEServer) startTLS(ctx context.Context) error {
+	logger := log.CtxLogger(ctx)
+
+	tlsConfig, err := NewTLSConfig(s.config.TLS)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to configure MCP SSE server TLS")
+
+		return err
+	}
+
+	s.httpServer.TLSConfig = tlsConfig
+
+	logger.Info().Msgf("starting MCP SSE server with TLS on %s", s.config.Address)
+
+	s.running = true
+
+	// the certificate is provided by the TLS config, to allow its reload
+	err = s.httpServer.ListenAndServeTLS("", "")
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error().Err(err).Msgf("failed to start MCP SSE server")
+
+		s.running = false
+
+		return err
+	}
+
+	return nil
+}
+
 func (s *MCPSSEServer) Stop(ctx context.Context) error {
 	logger := log.CtxLogger(ctx)
 
@@ -75,6 +140,111 @@ func (s *MCPSSEServer) Running() bool {
 	return s.running
 }
 
+// UseAuthenticator requires the requests of the SSE and message endpoints to be authenticated by a given
+// auth.MCPAuthenticator, and binds each session to the principal that opened it: the message requests of a session
+// from another principal are rejected. It must be called before Start or Mount.
+func (s *MCPSSEServer) UseAuthenticator(authenticator auth.MCPAuthenticator) {
+	s.authenticator = authenticator
+	s.httpServer.Handler = s.handler(s.server)
+}
+
+// Mount registers the SSE and message endpoints as routes of a given Echo instance, instead of starting a dedicated
+// listener: the routes go through the Echo middlewares (request ID, tracing, logging, metrics).
+func (s *MCPSSEServer) Mount(httpServer *echo.Echo) {
+	httpServer.GET(s.server.CompleteSsePath(), echo.WrapHandler(s.handler(s.server.SSEHandler())))
+	httpServer.POST(s.server.CompleteMessagePath(), echo.WrapHandler(s.handler(s.server.MessageHandler())))
+
+	s.mounted = true
+}
+
+func (s *MCPSSEServer) handler(handler http.Handler) http.Handler {
+	handler = s.limit(handler)
+
+	if s.authenticator == nil {
+		return handler
+	}
+
+	return auth.Middleware(s.authenticator, s.bind(handler))
+}
+
+// limit rejects the message requests with a body larger than the configured max message size with a 413.
+func (s *MCPSSEServer) limit(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Body != nil {
+			_, err := yokaimcpservercontext.ReadMessage(w, r, s.config.MaxMessageSize)
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				log.CtxLogger(r.Context()).Warn().
+					Str("system", "mcpserver").
+					Int64("mcpMaxMessageSize", s.config.MaxMessageSize).
+					Msg("rejected MCP request larger than the max message size")
+
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// sessionID generates the ID of a session opened by a given SSE request, and binds it to the request principal.
+func (s *MCPSSEServer) sessionID(ctx context.Context, r *http.Request) (string, error) {
+	sessionID := s.generator.Generate()
+
+	if principal := yokaimcpservercontext.CtxPrincipal(ctx); principal != "" {
+		s.principals.Store(sessionID, principal)
+
+		if holder, ok := ctx.Value(sessionIDHolderKey{}).(*string); ok {
+			*holder = sessionID
+		}
+	}
+
+	return sessionID, nil
+}
+
+// bind rejects the message requests from another principal than the one that opened their session, and releases the
+// binding of a session once its SSE request ends.
+func (s *MCPSSEServer) bind(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			var sessionID string
+
+			defer func() {
+				if sessionID != "" {
+					s.principals.Delete(sessionID)
+				}
+			}()
+
+			r = r.WithContext(context.WithValue(r.Context(), sessionIDHolderKey{}, &sessionID))
+		case http.MethodPost:
+			sessionID := r.URL.Query().Get("sessionId")
+			principal := yokaimcpservercontext.CtxPrincipal(r.Context())
+
+			if owner, ok := s.principals.Load(sessionID); ok && owner != principal {
+				log.CtxLogger(r.Context()).Warn().
+					Str("system", "mcpserver").
+					Str("mcpSessionID", sessionID).
+					Str("mcpPrincipal", principal).
+					Msg("rejected MCP request from another principal than the session one")
+
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *MCPSSEServer) Mounted() bool {
+	return s.mounted
+}
+
 func (s *MCPSSEServer) Info() map[string]any {
 	return map[string]any{
 		"config": map[string]any{
@@ -85,9 +255,20 @@ func (s *MCPSSEServer) Info() map[string]any {
 			"message_endpoint":    s.config.MessageEndpoint,
 			"keep_alive":          s.config.KeepAlive,
 			"keep_alive_interval": s.config.KeepAliveInterval.Seconds(),
+			"mount":               s.config.Mount,
+			"max_message_size":    s.config.MaxMessageSize,
+			"tls": map[string]any{
+				"enabled":     s.config.TLS.Enabled,
+				"cert":        s.config.TLS.Cert,
+				"client_ca":   s.config.TLS.ClientCA,
+				"client_auth": s.config.TLS.ClientAuth,
+				"min_version": s.config.TLS.MinVersion,
+			},
 		},
 		"status": map[string]any{
-			"running": s.running,
+			"running":       s.running,
+			"mounted":       s.mounted,
+			"authenticated": s.authenticator != nil,
 		},
 	}
 }
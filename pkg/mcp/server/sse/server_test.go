@@ -0,0 +1,148 @@
+package sse_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
+	"github.com/labstack/echo/v4"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openSession opens a SSE session with a given bearer token (if any), and returns the message endpoint of the session.
+func openSession(t *testing.T, url string, token string) string {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url+sse.DefaultSSEEndpoint, nil)
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		if endpoint, ok := strings.CutPrefix(line, "data: "); ok {
+			return strings.TrimSpace(endpoint)
+		}
+	}
+}
+
+const pingMessage = `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+func postMessage(t *testing.T, endpoint string, token string) int {
+	t.Helper()
+
+	return postMessageBody(t, endpoint, token, pingMessage)
+}
+
+func postMessageBody(t *testing.T, endpoint string, token string, body string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	return resp.StatusCode
+}
+
+func TestMCPSSEServerSessionBoundToPrincipal(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+
+	testServer := httptest.NewServer(httpServer)
+	t.Cleanup(testServer.Close)
+
+	sseServer := sse.NewMCPSSEServer(
+		server.NewMCPServer("test", "1.0.0"),
+		sse.MCPSSEServerConfig{Mount: true},
+		server.WithBaseURL(testServer.URL),
+	)
+	sseServer.UseAuthenticator(auth.NewStaticBearerTokenAuthenticator(map[string]string{
+		"alice": "alice-token",
+		"bob":   "bob-token",
+	}))
+	sseServer.Mount(httpServer)
+
+	aliceEndpoint := openSession(t, testServer.URL, "alice-token")
+	bobEndpoint := openSession(t, testServer.URL, "bob-token")
+
+	assert.Equal(t, http.StatusAccepted, postMessage(t, aliceEndpoint, "alice-token"))
+	assert.Equal(t, http.StatusAccepted, postMessage(t, bobEndpoint, "bob-token"))
+
+	assert.Equal(t, http.StatusForbidden, postMessage(t, aliceEndpoint, "bob-token"))
+	assert.Equal(t, http.StatusForbidden, postMessage(t, bobEndpoint, "alice-token"))
+	assert.Equal(t, http.StatusUnauthorized, postMessage(t, aliceEndpoint, ""))
+
+	// unknown sessions are rejected by the SSE server
+	assert.Equal(t, http.StatusBadRequest, postMessage(t, testServer.URL+"/message?sessionId=unknown", "alice-token"))
+}
+
+func TestMCPSSEServerMaxMessageSize(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+
+	testServer := httptest.NewServer(httpServer)
+	t.Cleanup(testServer.Close)
+
+	sseServer := sse.NewMCPSSEServer(
+		server.NewMCPServer("test", "1.0.0"),
+		sse.MCPSSEServerConfig{Mount: true, MaxMessageSize: int64(len(pingMessage))},
+		server.WithBaseURL(testServer.URL),
+	)
+	sseServer.Mount(httpServer)
+
+	endpoint := openSession(t, testServer.URL, "")
+
+	assert.Equal(t, http.StatusAccepted, postMessageBody(t, endpoint, "", pingMessage))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, postMessageBody(t, endpoint, "", pingMessage+" "))
+}
+
+func TestDefaultMCPSSEServerFactoryMaxMessageSize(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	srv := sse.NewDefaultMCPSSEServerFactory(mcptest.NewConfig(t, "app:\n  name: test\n")).Create(mcpServer)
+	assert.Equal(t, int64(sse.DefaultMaxMessageSize), srv.Config().MaxMessageSize)
+
+	srv = sse.NewDefaultMCPSSEServerFactory(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          max_message_size: 1024
+`)).Create(mcpServer)
+	assert.Equal(t, int64(1024), srv.Config().MaxMessageSize)
+	assert.Equal(t, int64(1024), srv.Info()["config"].(map[string]any)["max_message_size"])
+}
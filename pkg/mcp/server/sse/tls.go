@@ -0,0 +1,194 @@
+package sse
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	ClientAuthNone             = "none"
+	ClientAuthRequest          = "request"
+	ClientAuthRequire          = "require"
+	ClientAuthVerifyIfGiven    = "verify_if_given"
+	ClientAuthRequireAndVerify = "require_and_verify"
+	DefaultTLSMinVersion       = "1.2"
+)
+
+type MCPSSEServerTLSConfig struct {
+	Enabled    bool
+	Cert       string
+	Key        string
+	ClientCA   string
+	ClientAuth string
+	MinVersion string
+}
+
+// NewTLSConfig returns a tls.Config for a given MCPSSEServerTLSConfig, reloading the certificate and the client CA
+// from disk when their files change.
+func NewTLSConfig(config MCPSSEServerTLSConfig) (*tls.Config, error) {
+	minVersion, err := tlsVersion(config.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, err := tlsClientAuth(config.ClientAuth, config.ClientCA != "")
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := newReloadableFiles(func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(config.Cert, config.Key)
+
+		return &cert, err
+	}, config.Cert, config.Key)
+
+	// fail fast on invalid files, reloads are then done on handshakes
+	if _, err = certificate.Get(); err != nil {
+		return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: minVersion,
+		ClientAuth: clientAuth,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return certificate.Get()
+		},
+	}
+
+	if config.ClientCA == "" {
+		return tlsConfig, nil
+	}
+
+	clientCAs := newReloadableFiles(func() (*x509.CertPool, error) {
+		data, err := os.ReadFile(config.ClientCA)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", config.ClientCA)
+		}
+
+		return pool, nil
+	}, config.ClientCA)
+
+	if _, err = clientCAs.Get(); err != nil {
+		return nil, fmt.Errorf("cannot load TLS client CA: %w", err)
+	}
+
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := clientCAs.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		clientTLSConfig := tlsConfig.Clone()
+		clientTLSConfig.ClientCAs = pool
+		clientTLSConfig.GetConfigForClient = nil
+
+		return clientTLSConfig, nil
+	}
+
+	return tlsConfig, nil
+}
+
+func tlsVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0", "1.1":
+		return 0, fmt.Errorf("insecure TLS min version %q: must be at least %s", version, DefaultTLSMinVersion)
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS min version %q", version)
+	}
+}
+
+func tlsClientAuth(clientAuth string, hasClientCA bool) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case "":
+		if hasClientCA {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+
+		return tls.NoClientCert, nil
+	case ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest:
+		return tls.RequestClientCert, nil
+	case ClientAuthRequire:
+		return tls.RequireAnyClientCert, nil
+	case ClientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequireAndVerify:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid TLS client auth %q", clientAuth)
+	}
+}
+
+// reloadableFiles caches a value loaded from files, and reloads it when their modification time changes. If a reload
+// fails, the previously loaded value is kept.
+type reloadableFiles[T any] struct {
+	mu       sync.Mutex
+	files    []string
+	load     func() (T, error)
+	value    T
+	loaded   bool
+	modTimes []time.Time
+}
+
+func newReloadableFiles[T any](load func() (T, error), files ...string) *reloadableFiles[T] {
+	return &reloadableFiles[T]{
+		files:    files,
+		load:     load,
+		modTimes: make([]time.Time, len(files)),
+	}
+}
+
+func (r *reloadableFiles[T]) Get() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTimes := make([]time.Time, len(r.files))
+	changed := !r.loaded
+
+	for i, file := range r.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			if r.loaded {
+				return r.value, nil
+			}
+
+			return r.value, err
+		}
+
+		modTimes[i] = info.ModTime()
+		changed = changed || !modTimes[i].Equal(r.modTimes[i])
+	}
+
+	if !changed {
+		return r.value, nil
+	}
+
+	value, err := r.load()
+	if err != nil {
+		if r.loaded {
+			return r.value, nil
+		}
+
+		return r.value, err
+	}
+
+	r.value = value
+	r.loaded = true
+	r.modTimes = modTimes
+
+	return r.value, nil
+}
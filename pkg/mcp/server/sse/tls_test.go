@@ -0,0 +1,103 @@
+package sse_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	ca := newTestCertificate(t, "test-ca", true, nil)
+	caPath, _ := ca.write(t, dir, "ca")
+	certPath, keyPath := newTestCertificate(t, "localhost", false, ca).write(t, dir, "server")
+
+	tests := []struct {
+		name       string
+		config     sse.MCPSSEServerTLSConfig
+		minVersion uint16
+		clientAuth tls.ClientAuthType
+		err        string
+	}{
+		{
+			name:       "defaults",
+			config:     sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath},
+			minVersion: tls.VersionTLS12,
+			clientAuth: tls.NoClientCert,
+		},
+		{
+			name:       "client CA",
+			config:     sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, ClientCA: caPath, MinVersion: "1.3"},
+			minVersion: tls.VersionTLS13,
+			clientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:       "client auth",
+			config:     sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, ClientCA: caPath, ClientAuth: sse.ClientAuthVerifyIfGiven},
+			minVersion: tls.VersionTLS12,
+			clientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:   "TLS 1.0",
+			config: sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, MinVersion: "1.0"},
+			err:    `insecure TLS min version "1.0": must be at least 1.2`,
+		},
+		{
+			name:   "TLS 1.1",
+			config: sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, MinVersion: "1.1"},
+			err:    `insecure TLS min version "1.1": must be at least 1.2`,
+		},
+		{
+			name:   "invalid min version",
+			config: sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, MinVersion: "2.0"},
+			err:    `invalid TLS min version "2.0"`,
+		},
+		{
+			name:   "invalid client auth",
+			config: sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, ClientAuth: "always"},
+			err:    `invalid TLS client auth "always"`,
+		},
+		{
+			name:   "missing certificate",
+			config: sse.MCPSSEServerTLSConfig{Cert: "missing.crt", Key: "missing.key"},
+			err:    "cannot load TLS certificate",
+		},
+		{
+			name:   "invalid client CA",
+			config: sse.MCPSSEServerTLSConfig{Cert: certPath, Key: keyPath, ClientCA: keyPath},
+			err:    "cannot load TLS client CA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tlsConfig, err := sse.NewTLSConfig(tt.config)
+
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.minVersion, tlsConfig.MinVersion)
+			assert.Equal(t, tt.clientAuth, tlsConfig.ClientAuth)
+
+			cert, err := tlsConfig.GetCertificate(nil)
+			require.NoError(t, err)
+
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			require.NoError(t, err)
+			assert.Equal(t, "localhost", leaf.Subject.CommonName)
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/ankorstore/yokai/trace"
 	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
 	"github.com/mark3labs/mcp-go/server"
-	"go.opentelemetry.io/otel/attribute"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
@@ -47,23 +46,9 @@ func (h *DefaultMCPStdioServerContextHandler) Handle() server.StdioContextFunc {
 
 		ctx = yokaimcpservercontext.WithRequestID(ctx, rID)
 
-		// tracer propagation
+		// tracer propagation: the stdio context is created once per connection, the hooks start a root span per request
 		ctx = trace.WithContext(ctx, h.tracerProvider)
 
-		ctx, span := trace.CtxTracer(ctx).Start(
-			ctx,
-			"MCP",
-			oteltrace.WithNewRoot(),
-			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
-			oteltrace.WithAttributes(
-				attribute.String("system", "mcpserver"),
-				attribute.String("mcp.transport", "stdio"),
-				attribute.String("mcp.requestID", rID),
-			),
-		)
-
-		ctx = yokaimcpservercontext.WithRootSpan(ctx, span)
-
 		// logger propagation
 		logger := h.logger.
 			With().
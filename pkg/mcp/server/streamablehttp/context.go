@@ -0,0 +1,70 @@
+package streamablehttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/log"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/server"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var _ MCPStreamableHTTPServerContextHandler = (*DefaultMCPStreamableHTTPServerContextHandler)(nil)
+
+type MCPStreamableHTTPServerContextHandler interface {
+	Handle() server.HTTPContextFunc
+}
+
+type DefaultMCPStreamableHTTPServerContextHandler struct {
+	generator uuid.UuidGenerator
+	handler   *yokaimcpservercontext.TransportContextHandler
+}
+
+func NewDefaultMCPStreamableHTTPServerContextHandler(
+	config *config.Config,
+	generator uuid.UuidGenerator,
+	tracerProvider oteltrace.TracerProvider,
+	logger *log.Logger,
+) *DefaultMCPStreamableHTTPServerContextHandler {
+	return &DefaultMCPStreamableHTTPServerContextHandler{
+		generator: generator,
+		handler:   yokaimcpservercontext.NewTransportContextHandler(config, tracerProvider, logger),
+	}
+}
+
+func (h *DefaultMCPStreamableHTTPServerContextHandler) Handle() server.HTTPContextFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		start := time.Now()
+
+		// requestId propagation
+		rID := r.Header.Get("X-Request-Id")
+
+		if rID == "" {
+			rID = h.generator.Generate()
+			r.Header.Set("X-Request-Id", rID)
+		}
+
+		// trace context propagation, from headers then from the message _meta (which takes precedence)
+		ctx = yokaimcpservercontext.WithHeaderTraceContext(ctx, r.Header)
+
+		method, isRequest := "", false
+
+		// the body is consumed by the server beforehand: the message is provided by the MessageMiddleware
+		if message := yokaimcpservercontext.CtxMessage(ctx); message != nil {
+			ctx = yokaimcpservercontext.WithMessageTraceContext(ctx, message)
+			method, isRequest = yokaimcpservercontext.MessageKind(message)
+		}
+
+		return h.handler.Handle(ctx, start, yokaimcpservercontext.TransportMessage{
+			Transport: "streamable_http",
+			SessionID: r.Header.Get(server.HeaderKeySessionID),
+			RequestID: rID,
+			Method:    method,
+			IsRequest: isRequest,
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package streamablehttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace"
+	"github.com/ankorstore/yokai/trace/tracetest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/streamablehttp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestDefaultMCPStreamableHTTPServerContextHandler(t *testing.T) {
+	t.Parallel()
+
+	logs := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(log.WithOutputWriter(logs))
+	require.NoError(t, err)
+
+	spans := tracetest.NewDefaultTestTraceExporter()
+	tracerProvider, err := trace.NewDefaultTracerProviderFactory().Create(
+		trace.Global(false),
+		trace.WithSpanProcessor(trace.NewTestSpanProcessor(spans)),
+	)
+	require.NoError(t, err)
+
+	contextHandler := streamablehttp.NewDefaultMCPStreamableHTTPServerContextHandler(
+		mcptest.NewConfig(t, "app:\n  name: test\n"),
+		mcptest.NewSequenceUuidGenerator("request-id"),
+		tracerProvider,
+		logger,
+	).Handle()
+
+	var ctx context.Context
+
+	handler := streamablehttp.MessageMiddleware(streamablehttp.DefaultMaxMessageSize, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = contextHandler(r.Context(), r)
+	}))
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/mcp",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"book-list"}}`),
+	)
+	req.Header.Set(server.HeaderKeySessionID, "s1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "s1", yokaimcpservercontext.CtxSessionID(ctx))
+	assert.Equal(t, "request-id-1", yokaimcpservercontext.CtxRequestId(ctx))
+	assert.Equal(t, "request-id-1", req.Header.Get("X-Request-Id"))
+
+	yokaimcpservercontext.CtxRootSpan(ctx).End()
+
+	tracetest.AssertHasTraceSpan(
+		t,
+		spans,
+		"MCP tools/call",
+		attribute.String("mcp.transport", "streamable_http"),
+		attribute.String("mcp.sessionID", "s1"),
+		attribute.String("mcp.requestID", "request-id-1"),
+	)
+
+	log.CtxLogger(ctx).Info().Msg("test")
+
+	logtest.AssertHasLogRecord(t, logs, map[string]any{
+		"mcpTransport": "streamable_http",
+		"mcpSessionID": "s1",
+		"mcpRequestID": "request-id-1",
+		"message":      "test",
+	})
+}
+
+func TestDefaultMCPStreamableHTTPServerFactory(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	srv := streamablehttp.NewDefaultMCPStreamableHTTPServerFactory(mcptest.NewConfig(t, "app:\n  name: test\n")).Create(mcpServer)
+	assert.Equal(t, streamablehttp.DefaultAddr, srv.Config().Address)
+	assert.Equal(t, streamablehttp.DefaultEndpointPath, srv.Config().EndpointPath)
+	assert.Equal(t, int64(streamablehttp.DefaultMaxMessageSize), srv.Config().MaxMessageSize)
+
+	srv = streamablehttp.NewDefaultMCPStreamableHTTPServerFactory(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      transport:
+        streamable_http:
+          address: ":4444"
+          endpoint_path: "/custom"
+          stateless: true
+          heartbeat_interval: 10
+          max_message_size: 1024
+`)).Create(mcpServer)
+	assert.Equal(t, ":4444", srv.Config().Address)
+	assert.Equal(t, "/custom", srv.Config().EndpointPath)
+	assert.True(t, srv.Config().Stateless)
+	assert.Equal(t, int64(1024), srv.Config().MaxMessageSize)
+	assert.Equal(t, float64(10), srv.Info()["config"].(map[string]any)["heartbeat_interval"])
+}
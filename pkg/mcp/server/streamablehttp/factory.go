@@ -0,0 +1,81 @@
+package streamablehttp
+
+import (
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DefaultAddr         = ":3334"
+	DefaultEndpointPath = "/mcp"
+	// DefaultMaxMessageSize is the default max size in bytes of the MCP messages POST bodies.
+	DefaultMaxMessageSize = 1 << 20
+)
+
+var _ MCPStreamableHTTPServerFactory = (*DefaultMCPStreamableHTTPServerFactory)(nil)
+
+type MCPStreamableHTTPServerFactory interface {
+	Create(mcpServer *server.MCPServer, options ...server.StreamableHTTPOption) *MCPStreamableHTTPServer
+}
+
+type DefaultMCPStreamableHTTPServerFactory struct {
+	config *config.Config
+}
+
+func NewDefaultMCPStreamableHTTPServerFactory(config *config.Config) *DefaultMCPStreamableHTTPServerFactory {
+	return &DefaultMCPStreamableHTTPServerFactory{
+		config: config,
+	}
+}
+
+func (f *DefaultMCPStreamableHTTPServerFactory) Create(
+	mcpServer *server.MCPServer,
+	options ...server.StreamableHTTPOption,
+) *MCPStreamableHTTPServer {
+	addr := f.config.GetString("modules.mcp.server.transport.streamable_http.address")
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	endpointPath := f.config.GetString("modules.mcp.server.transport.streamable_http.endpoint_path")
+	if endpointPath == "" {
+		endpointPath = DefaultEndpointPath
+	}
+
+	stateless := f.config.GetBool("modules.mcp.server.transport.streamable_http.stateless")
+
+	var heartbeatInterval time.Duration
+	heartbeatIntervalConfig := f.config.GetInt("modules.mcp.server.transport.streamable_http.heartbeat_interval")
+	if heartbeatIntervalConfig != 0 {
+		heartbeatInterval = time.Duration(heartbeatIntervalConfig) * time.Second
+	}
+
+	maxMessageSize := int64(DefaultMaxMessageSize)
+	maxMessageSizeConfig := f.config.GetInt64("modules.mcp.server.transport.streamable_http.max_message_size")
+	if maxMessageSizeConfig != 0 {
+		maxMessageSize = maxMessageSizeConfig
+	}
+
+	srvConfig := MCPStreamableHTTPServerConfig{
+		Address:           addr,
+		EndpointPath:      endpointPath,
+		Stateless:         stateless,
+		HeartbeatInterval: heartbeatInterval,
+		MaxMessageSize:    maxMessageSize,
+	}
+
+	srvOptions := []server.StreamableHTTPOption{
+		server.WithEndpointPath(srvConfig.EndpointPath),
+		server.WithStateLess(srvConfig.Stateless),
+	}
+
+	if srvConfig.HeartbeatInterval > 0 {
+		srvOptions = append(srvOptions, server.WithHeartbeatInterval(srvConfig.HeartbeatInterval))
+	}
+
+	srvOptions = append(srvOptions, options...)
+
+	return NewMCPStreamableHTTPServer(mcpServer, srvConfig, srvOptions...)
+}
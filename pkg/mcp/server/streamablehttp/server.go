@@ -0,0 +1,143 @@
+package streamablehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ankorstore/yokai/log"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type MCPStreamableHTTPServerConfig struct {
+	Address           string
+	EndpointPath      string
+	Stateless         bool
+	HeartbeatInterval time.Duration
+	MaxMessageSize    int64
+}
+
+type MCPStreamableHTTPServer struct {
+	server     *server.StreamableHTTPServer
+	httpServer *http.Server
+	config     MCPStreamableHTTPServerConfig
+	running    bool
+}
+
+func NewMCPStreamableHTTPServer(
+	mcpServer *server.MCPServer,
+	config MCPStreamableHTTPServerConfig,
+	opts ...server.StreamableHTTPOption,
+) *MCPStreamableHTTPServer {
+	httpServer := &http.Server{
+		Addr: config.Address,
+	}
+
+	streamableHTTPServer := server.NewStreamableHTTPServer(
+		mcpServer,
+		append(opts, server.WithStreamableHTTPServer(httpServer))...,
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(config.EndpointPath, MessageMiddleware(config.MaxMessageSize, streamableHTTPServer))
+
+	httpServer.Handler = mux
+
+	return &MCPStreamableHTTPServer{
+		server:     streamableHTTPServer,
+		httpServer: httpServer,
+		config:     config,
+	}
+}
+
+func (s *MCPStreamableHTTPServer) Server() *server.StreamableHTTPServer {
+	return s.server
+}
+
+func (s *MCPStreamableHTTPServer) Config() MCPStreamableHTTPServerConfig {
+	return s.config
+}
+
+func (s *MCPStreamableHTTPServer) Start(ctx context.Context) error {
+	logger := log.CtxLogger(ctx)
+
+	logger.Info().Msgf("starting MCP Streamable HTTP server on %s", s.config.Address)
+
+	s.running = true
+
+	err := s.server.Start(s.config.Address)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error().Err(err).Msgf("failed to start MCP Streamable HTTP server")
+
+		s.running = false
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *MCPStreamableHTTPServer) Stop(ctx context.Context) error {
+	logger := log.CtxLogger(ctx)
+
+	logger.Info().Msg("stopping MCP Streamable HTTP server")
+
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to stop MCP Streamable HTTP server")
+	}
+
+	s.running = false
+
+	return err
+}
+
+func (s *MCPStreamableHTTPServer) Running() bool {
+	return s.running
+}
+
+func (s *MCPStreamableHTTPServer) Info() map[string]any {
+	return map[string]any{
+		"config": map[string]any{
+			"address":            s.config.Address,
+			"endpoint_path":      s.config.EndpointPath,
+			"stateless":          s.config.Stateless,
+			"heartbeat_interval": s.config.HeartbeatInterval.Seconds(),
+			"max_message_size":   s.config.MaxMessageSize,
+		},
+		"status": map[string]any{
+			"running": s.running,
+		},
+	}
+}
+
+// MessageMiddleware makes the POST request body (the MCP message) available in the request context, since the
+// Streamable HTTP server consumes it before invoking the context handler. Bodies larger than a given max message size
+// (if positive) are rejected with a 413.
+func MessageMiddleware(maxMessageSize int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.Body != nil {
+			body, err := yokaimcpservercontext.ReadMessage(w, r, maxMessageSize)
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				log.CtxLogger(r.Context()).Warn().
+					Str("system", "mcpserver").
+					Int64("mcpMaxMessageSize", maxMessageSize).
+					Msg("rejected MCP request larger than the max message size")
+
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+				return
+			}
+
+			if err == nil {
+				r = r.WithContext(yokaimcpservercontext.WithMessage(r.Context(), body))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
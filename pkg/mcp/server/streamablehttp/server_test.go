@@ -0,0 +1,48 @@
+package streamablehttp_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/streamablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageMiddlewareMaxMessageSize(t *testing.T) {
+	t.Parallel()
+
+	message := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+	var served bool
+	var ctxMessage, body []byte
+
+	handler := streamablehttp.MessageMiddleware(int64(len(message)), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+		ctxMessage = yokaimcpservercontext.CtxMessage(r.Context())
+
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(message)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, served)
+	assert.Equal(t, message, string(ctxMessage))
+	assert.Equal(t, message, string(body))
+
+	served = false
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(message+" ")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, served)
+}
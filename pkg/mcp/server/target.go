@@ -0,0 +1,71 @@
+package server
+
+import (
+	"github.com/ankorstore/yokai/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OtherMetricTarget is the metric target label value used for targets not registered or not allowed.
+const OtherMetricTarget = "other"
+
+// MCPServerMetricTargetResolver bounds the cardinality of the metrics target label, by resolving request targets to
+// registered tool, prompt, resource or resource template names, and by restricting them to an optional allow-list
+// configured in modules.mcp.server.metrics.targets.allow: anything else falls into the "other" bucket.
+type MCPServerMetricTargetResolver struct {
+	registry *MCPServerRegistry
+	allowed  map[string]struct{}
+}
+
+// NewMCPServerMetricTargetResolver returns a new MCPServerMetricTargetResolver.
+func NewMCPServerMetricTargetResolver(config *config.Config, registry *MCPServerRegistry) *MCPServerMetricTargetResolver {
+	return &MCPServerMetricTargetResolver{
+		registry: registry,
+		allowed:  toSet(config.GetStringSlice("modules.mcp.server.metrics.targets.allow")),
+	}
+}
+
+// Resolve returns the bounded metric target label value for a given MCP request.
+func (r *MCPServerMetricTargetResolver) Resolve(method mcp.MCPMethod, message any) string {
+	target, ok := r.resolve(method, message)
+	if !ok {
+		return OtherMetricTarget
+	}
+
+	if target != "" && len(r.allowed) > 0 {
+		if _, allowed := r.allowed[target]; !allowed {
+			return OtherMetricTarget
+		}
+	}
+
+	return target
+}
+
+func (r *MCPServerMetricTargetResolver) resolve(method mcp.MCPMethod, message any) (string, bool) {
+	switch method {
+	case mcp.MethodToolsCall:
+		if req, ok := message.(*mcp.CallToolRequest); ok {
+			return req.Params.Name, r.registry.HasTool(req.Params.Name)
+		}
+	case mcp.MethodPromptsGet:
+		if req, ok := message.(*mcp.GetPromptRequest); ok {
+			return req.Params.Name, r.registry.HasPrompt(req.Params.Name)
+		}
+	case mcp.MethodResourcesRead:
+		if req, ok := message.(*mcp.ReadResourceRequest); ok {
+			return r.registry.ResolveResourceName(req.Params.URI)
+		}
+	case mcp.MethodCompletionComplete:
+		if req, ok := message.(*mcp.CompleteRequest); ok {
+			switch ref := req.Params.Ref.(type) {
+			case mcp.PromptReference:
+				return ref.Name, r.registry.HasPrompt(ref.Name)
+			case mcp.ResourceReference:
+				return r.registry.ResolveResourceName(ref.URI)
+			}
+
+			return "", false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTargetRegistry(t *testing.T) *yokaimcpserver.MCPServerRegistry {
+	t.Helper()
+
+	registry, err := yokaimcpserver.NewMCPServerRegistry(
+		mcptest.NewConfig(t, allCapabilitiesConfig),
+		[]yokaimcpserver.MCPServerTool{newTestTool("book-list"), newTestTool("book-get")},
+		[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}},
+		[]yokaimcpserver.MCPServerResource{&testResource{name: "weather", uri: "weather://current"}},
+		[]yokaimcpserver.MCPServerResourceTemplate{
+			&testResourceTemplate{name: "book", uri: "books://{id}"},
+			&testResourceTemplate{name: "author", uri: "authors://{id}/books"},
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	return registry
+}
+
+func TestMCPServerRegistryLookups(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestTargetRegistry(t)
+
+	assert.True(t, registry.HasTool("book-list"))
+	assert.False(t, registry.HasTool("unknown"))
+	assert.True(t, registry.HasPrompt("greet"))
+	assert.False(t, registry.HasPrompt("unknown"))
+
+	name, ok := registry.ResolveResourceName("weather://current")
+	assert.True(t, ok)
+	assert.Equal(t, "weather", name)
+
+	name, ok = registry.ResolveResourceName("books://42")
+	assert.True(t, ok)
+	assert.Equal(t, "book", name)
+
+	name, ok = registry.ResolveResourceName("authors://42/books")
+	assert.True(t, ok)
+	assert.Equal(t, "author", name)
+
+	_, ok = registry.ResolveResourceName("unknown://42")
+	assert.False(t, ok)
+}
+
+func TestMCPServerMetricTargetResolver(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestTargetRegistry(t)
+
+	toolRequest := func(name string) *mcp.CallToolRequest {
+		return newTestToolRequest(name, nil)
+	}
+
+	promptRequest := &mcp.GetPromptRequest{}
+	promptRequest.Params.Name = "greet"
+
+	resourceRequest := &mcp.ReadResourceRequest{}
+	resourceRequest.Params.URI = "books://42"
+
+	unknownResourceRequest := &mcp.ReadResourceRequest{}
+	unknownResourceRequest.Params.URI = "random://42"
+
+	completeRequest := &mcp.CompleteRequest{}
+	completeRequest.Params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: "books://{id}"}
+
+	resolver := yokaimcpserver.NewMCPServerMetricTargetResolver(mcptest.NewConfig(t, "app:\n  name: test\n"), registry)
+
+	assert.Equal(t, "book-list", resolver.Resolve(mcp.MethodToolsCall, toolRequest("book-list")))
+	assert.Equal(t, yokaimcpserver.OtherMetricTarget, resolver.Resolve(mcp.MethodToolsCall, toolRequest("random-123")))
+	assert.Equal(t, "greet", resolver.Resolve(mcp.MethodPromptsGet, promptRequest))
+	assert.Equal(t, "book", resolver.Resolve(mcp.MethodResourcesRead, resourceRequest))
+	assert.Equal(t, yokaimcpserver.OtherMetricTarget, resolver.Resolve(mcp.MethodResourcesRead, unknownResourceRequest))
+	assert.Equal(t, "book", resolver.Resolve(mcp.MethodCompletionComplete, completeRequest))
+	assert.Equal(t, "", resolver.Resolve(mcp.MethodToolsList, &mcp.ListToolsRequest{}))
+
+	resolver = yokaimcpserver.NewMCPServerMetricTargetResolver(mcptest.NewConfig(t, `
+modules:
+  mcp:
+    server:
+      metrics:
+        targets:
+          allow:
+            - "book-list"
+`), registry)
+
+	assert.Equal(t, "book-list", resolver.Resolve(mcp.MethodToolsCall, toolRequest("book-list")))
+	assert.Equal(t, yokaimcpserver.OtherMetricTarget, resolver.Resolve(mcp.MethodToolsCall, toolRequest("book-get")))
+	assert.Equal(t, "", resolver.Resolve(mcp.MethodToolsList, &mcp.ListToolsRequest{}))
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var _ MCPServerTool = (*MCPServerTypedTool[any, any])(nil)
+
+// MCPServerTypedToolHandlerFunc is the handler of a MCPServerTypedTool, receiving a decoded and validated input.
+type MCPServerTypedToolHandlerFunc[In any, Out any] func(ctx context.Context, input In) (Out, error)
+
+// MCPServerTypedTool is a MCPServerTool whose input JSON schema is derived from the In struct tags (see
+// MCPServerSchemaField), and whose Out result is automatically serialized:
+//   - *mcp.CallToolResult is returned as is
+//   - string is returned as text content
+//   - any other type is returned as JSON text content
+type MCPServerTypedTool[In any, Out any] struct {
+	name    string
+	schema  mcp.ToolInputSchema
+	fields  []MCPServerSchemaField
+	options []mcp.ToolOption
+	handler MCPServerTypedToolHandlerFunc[In, Out]
+	source  string
+}
+
+// NewMCPServerTypedTool returns a new MCPServerTypedTool, or an error if the In type cannot be described by a JSON schema.
+func NewMCPServerTypedTool[In any, Out any](
+	name string,
+	handler MCPServerTypedToolHandlerFunc[In, Out],
+	options ...mcp.ToolOption,
+) (*MCPServerTypedTool[In, Out], error) {
+	inputType := reflect.TypeFor[In]()
+
+	properties, required, err := Schema(inputType)
+	if err != nil {
+		return nil, fmt.Errorf("cannot derive MCP tool %q input schema: %w", name, err)
+	}
+
+	source := ""
+	if _, file, line, ok := runtime.Caller(1); ok {
+		source = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	return &MCPServerTypedTool[In, Out]{
+		name: name,
+		schema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: properties,
+			Required:   required,
+		},
+		fields:  SchemaFields(inputType),
+		options: options,
+		handler: handler,
+		source:  source,
+	}, nil
+}
+
+func (t *MCPServerTypedTool[In, Out]) Name() string {
+	return t.name
+}
+
+// Source returns the file:line location where the MCPServerTypedTool was created.
+func (t *MCPServerTypedTool[In, Out]) Source() string {
+	return t.source
+}
+
+func (t *MCPServerTypedTool[In, Out]) Options() []mcp.ToolOption {
+	options := []mcp.ToolOption{
+		func(tool *mcp.Tool) {
+			tool.InputSchema = t.schema
+		},
+	}
+
+	return append(options, t.options...)
+}
+
+func (t *MCPServerTypedTool[In, Out]) Handle() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		input, err := t.Decode(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		output, err := t.handler(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Encode(output)
+	}
+}
+
+// Decode decodes and validates the arguments of a given mcp.CallToolRequest into an In value, applying defaults.
+func (t *MCPServerTypedTool[In, Out]) Decode(request mcp.CallToolRequest) (In, error) {
+	var input In
+
+	arguments, errs := validateArguments(t.fields, request.GetArguments(), "")
+
+	if len(errs) > 0 {
+		return input, fmt.Errorf("invalid arguments: %s", strings.Join(errs, ", "))
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return input, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	err = json.Unmarshal(data, &input)
+	if err != nil {
+		return input, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	return input, nil
+}
+
+// Encode encodes a given Out value into a mcp.CallToolResult.
+func (t *MCPServerTypedTool[In, Out]) Encode(output Out) (*mcp.CallToolResult, error) {
+	switch o := any(output).(type) {
+	case *mcp.CallToolResult:
+		return o, nil
+	case string:
+		return mcp.NewToolResultText(o), nil
+	default:
+		data, err := json.Marshal(o)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode MCP tool %q result: %w", t.name, err)
+		}
+
+		return mcp.NewToolResultText(string(data)), nil
+	}
+}
+
+// validateArguments validates a copy of the given arguments against the given fields, applying defaults, and recursing
+// into nested struct values, so that nested required and enum constraints are enforced as well.
+func validateArguments(fields []MCPServerSchemaField, arguments map[string]any, prefix string) (map[string]any, []string) {
+	validated := make(map[string]any, len(arguments))
+	for name, value := range arguments {
+		validated[name] = value
+	}
+
+	var errs []string
+
+	for _, field := range fields {
+		name := prefix + field.Name
+		value, ok := validated[field.Name]
+
+		if !ok || value == nil {
+			if field.Required {
+				errs = append(errs, fmt.Sprintf("argument %q is required", name))
+			} else if field.Default != "" {
+				if v, err := parseValue(field.Type, field.Default); err == nil {
+					validated[field.Name] = v
+				}
+			}
+
+			continue
+		}
+
+		if len(field.Enum) > 0 && !slices.Contains(field.Enum, fmt.Sprintf("%v", value)) {
+			errs = append(errs, fmt.Sprintf("argument %q must be one of [%s]", name, strings.Join(field.Enum, ", ")))
+		}
+
+		var nestedErrs []string
+		validated[field.Name], nestedErrs = validateNestedArgument(field.Type, value, name)
+		errs = append(errs, nestedErrs...)
+	}
+
+	return validated, errs
+}
+
+// validateNestedArgument validates a given argument value if it is a struct, or a slice or array of structs.
+func validateNestedArgument(t reflect.Type, value any, name string) (any, []string) {
+	t = indirectType(t)
+
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Struct:
+		if object, ok := value.(map[string]any); ok {
+			return validateArguments(SchemaFields(t), object, name+".")
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := value.([]any); ok && indirectType(t.Elem()).Kind() == reflect.Struct {
+			var errs []string
+
+			validated := make([]any, len(items))
+			for i, item := range items {
+				var itemErrs []string
+				validated[i], itemErrs = validateNestedArgument(t.Elem(), item, fmt.Sprintf("%s[%d]", name, i))
+				errs = append(errs, itemErrs...)
+			}
+
+			return validated, errs
+		}
+	}
+
+	return value, nil
+}
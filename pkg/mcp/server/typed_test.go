@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTypedTool(t *testing.T) *yokaimcpserver.MCPServerTypedTool[schemaTestInput, schemaTestInput] {
+	t.Helper()
+
+	tool, err := yokaimcpserver.NewMCPServerTypedTool(
+		"typed",
+		func(ctx context.Context, input schemaTestInput) (schemaTestInput, error) {
+			return input, nil
+		},
+		mcp.WithDescription("typed tool"),
+	)
+	require.NoError(t, err)
+
+	return tool
+}
+
+func newTestCallToolRequest(arguments map[string]any) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "typed"
+	request.Params.Arguments = arguments
+
+	return request
+}
+
+func TestMCPServerTypedToolOptions(t *testing.T) {
+	t.Parallel()
+
+	tool := newTestTypedTool(t)
+
+	mcpTool := mcp.NewTool(tool.Name(), tool.Options()...)
+	assert.Equal(t, "typed tool", mcpTool.Description)
+	assert.Equal(t, []string{"title"}, mcpTool.InputSchema.Required)
+	assert.Contains(t, mcpTool.InputSchema.Properties, "address")
+	assert.NotEmpty(t, tool.Source())
+}
+
+func TestMCPServerTypedToolDecode(t *testing.T) {
+	t.Parallel()
+
+	tool := newTestTypedTool(t)
+
+	arguments := map[string]any{
+		"title":     "Dune",
+		"address":   map[string]any{"city": "Paris"},
+		"addresses": []any{map[string]any{"city": "London", "country": "uk"}},
+	}
+
+	input, err := tool.Decode(newTestCallToolRequest(arguments))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Dune", input.Title)
+	assert.Equal(t, 100, input.Pages)
+	assert.Equal(t, &schemaTestAddress{City: "Paris", Country: "fr"}, input.Address)
+	assert.Equal(t, []schemaTestAddress{{City: "London", Country: "uk"}}, input.Addresses)
+
+	// the request arguments are left untouched
+	assert.Equal(t, map[string]any{"city": "Paris"}, arguments["address"])
+}
+
+func TestMCPServerTypedToolDecodeValidation(t *testing.T) {
+	t.Parallel()
+
+	tool := newTestTypedTool(t)
+
+	_, err := tool.Decode(newTestCallToolRequest(map[string]any{
+		"genre":     "comedy",
+		"address":   map[string]any{"country": "de"},
+		"addresses": []any{map[string]any{"city": "Paris"}, map[string]any{"country": "us"}},
+	}))
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), `argument "title" is required`)
+	assert.Contains(t, err.Error(), `argument "genre" must be one of [horror, romance]`)
+	assert.Contains(t, err.Error(), `argument "address.city" is required`)
+	assert.Contains(t, err.Error(), `argument "address.country" must be one of [fr, uk]`)
+	assert.NotContains(t, err.Error(), `"addresses[0].city"`)
+	assert.Contains(t, err.Error(), `argument "addresses[1].city" is required`)
+	assert.Contains(t, err.Error(), `argument "addresses[1].country" must be one of [fr, uk]`)
+}
+
+func TestMCPServerTypedToolHandle(t *testing.T) {
+	t.Parallel()
+
+	tool := newTestTypedTool(t)
+
+	result, err := tool.Handle()(context.Background(), newTestCallToolRequest(map[string]any{"title": "Dune"}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"title":"Dune"`)
+
+	result, err = tool.Handle()(context.Background(), newTestCallToolRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, `invalid arguments: argument "title" is required`, result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestNewMCPServerTypedToolWithRecursiveInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := yokaimcpserver.NewMCPServerTypedTool(
+		"recursive",
+		func(ctx context.Context, input schemaTestRecursive) (string, error) {
+			return "", nil
+		},
+	)
+	assert.ErrorContains(t, err, `cannot derive MCP tool "recursive" input schema`)
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"fmt"
 	"reflect"
 	"runtime"
 	"strings"
+	"unicode/utf8"
 )
 
 // FuncName returns a readable func name for code browsing purposes
@@ -11,6 +13,73 @@ func FuncName(f any) string {
 	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
 }
 
+// TypeName returns the fully qualified type name of a given value, for code browsing purposes.
+func TypeName(v any) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return ""
+	}
+
+	prefix := ""
+	for t.Kind() == reflect.Pointer {
+		prefix = prefix + "*"
+		t = t.Elem()
+	}
+
+	if t.PkgPath() == "" {
+		return prefix + t.String()
+	}
+
+	return fmt.Sprintf("%s%s.%s", prefix, t.PkgPath(), t.Name())
+}
+
+// SourceLocation returns the file:line location of a given value implementation, for code browsing purposes.
+//
+// If the value provides a Source() string method, it is used. Otherwise, the location of the topmost method declared
+// by the value type is returned.
+func SourceLocation(v any) string {
+	if s, ok := v.(interface{ Source() string }); ok {
+		return s.Source()
+	}
+
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return ""
+	}
+
+	location := ""
+	minLine := 0
+
+	for i := range t.NumMethod() {
+		fn := runtime.FuncForPC(t.Method(i).Func.Pointer())
+		if fn == nil {
+			continue
+		}
+
+		file, line := fn.FileLine(fn.Entry())
+		if file != "" && !strings.HasPrefix(file, "<") && (minLine == 0 || line < minLine) {
+			location = fmt.Sprintf("%s:%d", file, line)
+			minLine = line
+		}
+	}
+
+	return location
+}
+
+// Truncate truncates a given string to a maximum size in bytes, without splitting runes. A size of 0 or less means no limit.
+func Truncate(str string, size int) string {
+	if size <= 0 || len(str) <= size {
+		return str
+	}
+
+	cut := size
+	for cut > 0 && !utf8.RuneStart(str[cut]) {
+		cut--
+	}
+
+	return fmt.Sprintf("%s...(truncated %d bytes)", str[:cut], len(str)-cut)
+}
+
 // Sanitize transforms a given string to not contain spaces or dashes, and to be in lower case.
 func Sanitize(str string) string {
 	san := strings.ReplaceAll(str, " ", "_")
@@ -0,0 +1,39 @@
+package server_test
+
+import (
+	"strings"
+	"testing"
+
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", yokaimcpserver.TypeName(nil))
+	assert.Equal(t, "string", yokaimcpserver.TypeName("value"))
+	assert.Equal(t, "*github.com/ekkinox/yokai-mcp/pkg/mcp/server_test.testTool", yokaimcpserver.TypeName(newTestTool("tool")))
+}
+
+func TestSourceLocation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", yokaimcpserver.SourceLocation(nil))
+
+	location := yokaimcpserver.SourceLocation(newTestTool("tool"))
+	assert.True(t, strings.Contains(location, "helper_test.go:"), location)
+
+	tool := newTestTypedTool(t)
+	assert.Equal(t, tool.Source(), yokaimcpserver.SourceLocation(tool))
+	assert.True(t, strings.Contains(tool.Source(), "typed_test.go:"), tool.Source())
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "hello", yokaimcpserver.Truncate("hello", 0))
+	assert.Equal(t, "hello", yokaimcpserver.Truncate("hello", 10))
+	assert.Equal(t, "he...(truncated 3 bytes)", yokaimcpserver.Truncate("hello", 2))
+	assert.Equal(t, "...(truncated 2 bytes)", yokaimcpserver.Truncate("é", 1))
+}
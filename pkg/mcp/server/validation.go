@@ -0,0 +1,112 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// toolNameRegexp matches tool names allowed by the MCP specification.
+var toolNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,128}$`)
+
+// ValidateMCPServerRegistrations validates the provided MCP registrations, and returns an aggregated error for
+// duplicated names, duplicated resource URIs, malformed resource template URIs and invalid tool names.
+func ValidateMCPServerRegistrations(
+	tools []MCPServerTool,
+	prompts []MCPServerPrompt,
+	resources []MCPServerResource,
+	resourceTemplates []MCPServerResourceTemplate,
+) error {
+	var errs []error
+
+	toolNames := make(map[string]struct{}, len(tools))
+	for _, tool := range tools {
+		name := tool.Name()
+
+		if !toolNameRegexp.MatchString(name) {
+			errs = append(errs, fmt.Errorf("invalid MCP tool name %q: must match %s", name, toolNameRegexp.String()))
+		}
+
+		if _, ok := toolNames[name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP tool name %q", name))
+		}
+
+		toolNames[name] = struct{}{}
+	}
+
+	promptNames := make(map[string]struct{}, len(prompts))
+	for _, prompt := range prompts {
+		name := prompt.Name()
+
+		if name == "" {
+			errs = append(errs, errors.New("invalid MCP prompt name: cannot be empty"))
+		}
+
+		if _, ok := promptNames[name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP prompt name %q", name))
+		}
+
+		promptNames[name] = struct{}{}
+	}
+
+	resourceNames := make(map[string]struct{}, len(resources))
+	resourceURIs := make(map[string]struct{}, len(resources))
+	for _, resource := range resources {
+		name := resource.Name()
+		uri := resource.URI()
+
+		if name == "" {
+			errs = append(errs, fmt.Errorf("invalid MCP resource name for URI %q: cannot be empty", uri))
+		}
+
+		if _, ok := resourceNames[name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP resource name %q", name))
+		}
+
+		if u, err := url.Parse(uri); err != nil || u.Scheme == "" {
+			errs = append(errs, fmt.Errorf("invalid MCP resource %q URI %q: must be an absolute URI", name, uri))
+		}
+
+		if _, ok := resourceURIs[uri]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP resource URI %q", uri))
+		}
+
+		resourceNames[name] = struct{}{}
+		resourceURIs[uri] = struct{}{}
+	}
+
+	resourceTemplateNames := make(map[string]struct{}, len(resourceTemplates))
+	resourceTemplateURIs := make(map[string]struct{}, len(resourceTemplates))
+	for _, resourceTemplate := range resourceTemplates {
+		name := resourceTemplate.Name()
+		uri := resourceTemplate.URI()
+
+		if name == "" {
+			errs = append(errs, fmt.Errorf("invalid MCP resource template name for URI %q: cannot be empty", uri))
+		}
+
+		if _, ok := resourceTemplateNames[name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP resource template name %q", name))
+		}
+
+		if _, err := uritemplate.New(uri); err != nil {
+			errs = append(errs, fmt.Errorf("invalid MCP resource template %q URI %q: %w", name, uri, err))
+		}
+
+		if _, ok := resourceTemplateURIs[uri]; ok {
+			errs = append(errs, fmt.Errorf("duplicate MCP resource template URI %q", uri))
+		}
+
+		resourceTemplateNames[name] = struct{}{}
+		resourceTemplateURIs[uri] = struct{}{}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid MCP server registrations: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
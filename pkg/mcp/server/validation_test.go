@@ -0,0 +1,66 @@
+package server_test
+
+import (
+	"testing"
+
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	yokaimcpserver "github.com/ekkinox/yokai-mcp/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMCPServerRegistrations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid registrations", func(t *testing.T) {
+		t.Parallel()
+
+		err := yokaimcpserver.ValidateMCPServerRegistrations(
+			[]yokaimcpserver.MCPServerTool{newTestTool("list-books"), newTestTool("create_book.v2")},
+			[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}},
+			[]yokaimcpserver.MCPServerResource{&testResource{name: "weather", uri: "weather://current"}},
+			[]yokaimcpserver.MCPServerResourceTemplate{&testResourceTemplate{name: "book", uri: "books://{id}"}},
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		t.Parallel()
+
+		err := yokaimcpserver.ValidateMCPServerRegistrations(
+			[]yokaimcpserver.MCPServerTool{newTestTool("list-books"), newTestTool("list-books"), newTestTool("invalid name")},
+			[]yokaimcpserver.MCPServerPrompt{&testPrompt{name: "greet"}, &testPrompt{name: "greet"}, &testPrompt{}},
+			[]yokaimcpserver.MCPServerResource{
+				&testResource{name: "weather", uri: "weather://current"},
+				&testResource{name: "forecast", uri: "weather://current"},
+				&testResource{name: "relative", uri: "relative/path"},
+			},
+			[]yokaimcpserver.MCPServerResourceTemplate{&testResourceTemplate{name: "book", uri: "books://{id"}},
+		)
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), `duplicate MCP tool name "list-books"`)
+		assert.Contains(t, err.Error(), `invalid MCP tool name "invalid name"`)
+		assert.Contains(t, err.Error(), `duplicate MCP prompt name "greet"`)
+		assert.Contains(t, err.Error(), "invalid MCP prompt name: cannot be empty")
+		assert.Contains(t, err.Error(), `duplicate MCP resource URI "weather://current"`)
+		assert.Contains(t, err.Error(), `invalid MCP resource "relative" URI "relative/path"`)
+		assert.Contains(t, err.Error(), `invalid MCP resource template "book" URI "books://{id"`)
+	})
+}
+
+func TestNewMCPServerRegistryFailsOnInvalidRegistrations(t *testing.T) {
+	t.Parallel()
+
+	_, err := yokaimcpserver.NewMCPServerRegistry(
+		mcptest.NewConfig(t, allCapabilitiesConfig),
+		[]yokaimcpserver.MCPServerTool{newTestTool("list-books"), newTestTool("list-books")},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	assert.ErrorContains(t, err, `duplicate MCP tool name "list-books"`)
+}
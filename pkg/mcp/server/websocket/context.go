@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/log"
+	yokaimcpservercontext "github.com/ekkinox/yokai-mcp/pkg/mcp/server/context"
+	"github.com/mark3labs/mcp-go/server"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var _ MCPWebSocketServerContextHandler = (*DefaultMCPWebSocketServerContextHandler)(nil)
+
+type MCPWebSocketServerContextHandler interface {
+	Handle() MCPWebSocketContextFunc
+}
+
+type DefaultMCPWebSocketServerContextHandler struct {
+	generator uuid.UuidGenerator
+	handler   *yokaimcpservercontext.TransportContextHandler
+}
+
+func NewDefaultMCPWebSocketServerContextHandler(
+	config *config.Config,
+	generator uuid.UuidGenerator,
+	tracerProvider oteltrace.TracerProvider,
+	logger *log.Logger,
+) *DefaultMCPWebSocketServerContextHandler {
+	return &DefaultMCPWebSocketServerContextHandler{
+		generator: generator,
+		handler:   yokaimcpservercontext.NewTransportContextHandler(config, tracerProvider, logger),
+	}
+}
+
+func (h *DefaultMCPWebSocketServerContextHandler) Handle() MCPWebSocketContextFunc {
+	return func(ctx context.Context, r *http.Request, message []byte) context.Context {
+		start := time.Now()
+
+		// sessionId propagation
+		sID := ""
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sID = session.SessionID()
+		}
+
+		// trace context propagation, from the connection upgrade request headers then from the message _meta (which
+		// takes precedence)
+		ctx = yokaimcpservercontext.WithHeaderTraceContext(ctx, r.Header)
+		ctx = yokaimcpservercontext.WithMessageTraceContext(ctx, message)
+
+		method, isRequest := yokaimcpservercontext.MessageKind(message)
+
+		return h.handler.Handle(ctx, start, yokaimcpservercontext.TransportMessage{
+			Transport: Transport,
+			SessionID: sID,
+			// the connection upgrade request is shared by all messages: one requestId per message
+			RequestID: h.generator.Generate(),
+			Method:    method,
+			IsRequest: isRequest,
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DefaultAddr           = ":3335"
+	DefaultPath           = "/ws"
+	DefaultPingInterval   = 30 * time.Second
+	DefaultMaxMessageSize = 1 << 20
+)
+
+var _ MCPWebSocketServerFactory = (*DefaultMCPWebSocketServerFactory)(nil)
+
+type MCPWebSocketServerFactory interface {
+	Create(mcpServer *server.MCPServer, options ...MCPWebSocketServerOption) *MCPWebSocketServer
+}
+
+type DefaultMCPWebSocketServerFactory struct {
+	config *config.Config
+}
+
+func NewDefaultMCPWebSocketServerFactory(config *config.Config) *DefaultMCPWebSocketServerFactory {
+	return &DefaultMCPWebSocketServerFactory{
+		config: config,
+	}
+}
+
+func (f *DefaultMCPWebSocketServerFactory) Create(
+	mcpServer *server.MCPServer,
+	options ...MCPWebSocketServerOption,
+) *MCPWebSocketServer {
+	addr := f.config.GetString("modules.mcp.server.transport.websocket.address")
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	path := f.config.GetString("modules.mcp.server.transport.websocket.path")
+	if path == "" {
+		path = DefaultPath
+	}
+
+	pingInterval := DefaultPingInterval
+	pingIntervalConfig := f.config.GetInt("modules.mcp.server.transport.websocket.ping_interval")
+	if pingIntervalConfig != 0 {
+		pingInterval = time.Duration(pingIntervalConfig) * time.Second
+	}
+
+	maxMessageSize := int64(DefaultMaxMessageSize)
+	maxMessageSizeConfig := f.config.GetInt64("modules.mcp.server.transport.websocket.max_message_size")
+	if maxMessageSizeConfig != 0 {
+		maxMessageSize = maxMessageSizeConfig
+	}
+
+	workers := f.config.GetInt("modules.mcp.server.transport.websocket.workers")
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	srvConfig := MCPWebSocketServerConfig{
+		Address:        addr,
+		Path:           path,
+		PingInterval:   pingInterval,
+		MaxMessageSize: maxMessageSize,
+		Workers:        workers,
+	}
+
+	return NewMCPWebSocketServer(mcpServer, srvConfig, options...)
+}
@@ -0,0 +1,346 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/log"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DefaultWriteTimeout = 10 * time.Second
+	DefaultWorkers      = 10
+)
+
+// MCPWebSocketContextFunc allows to enrich the context of each message received on a WebSocket connection, from the
+// connection upgrade request and the raw message.
+type MCPWebSocketContextFunc func(ctx context.Context, r *http.Request, message []byte) context.Context
+
+type MCPWebSocketServerOption func(*MCPWebSocketServer)
+
+func WithWebSocketContextFunc(fn MCPWebSocketContextFunc) MCPWebSocketServerOption {
+	return func(s *MCPWebSocketServer) {
+		s.contextFunc = fn
+	}
+}
+
+// WithSessionIDGenerator sets the generator of the sessions IDs (uuid.DefaultUuidGenerator by default).
+func WithSessionIDGenerator(generator uuid.UuidGenerator) MCPWebSocketServerOption {
+	return func(s *MCPWebSocketServer) {
+		s.generator = generator
+	}
+}
+
+type MCPWebSocketServerConfig struct {
+	Address        string
+	Path           string
+	PingInterval   time.Duration
+	MaxMessageSize int64
+	Workers        int
+}
+
+// MCPWebSocketServer exposes an MCP server over WebSocket: each text message is a JSON-RPC message, and responses and
+// notifications are sent back as text messages on the same connection.
+//
+// The messages of a connection are handled concurrently by a bounded pool of workers (MCPWebSocketServerConfig.Workers):
+// once all workers are busy, the connection is not read until one becomes available.
+type MCPWebSocketServer struct {
+	server      *server.MCPServer
+	httpServer  *http.Server
+	upgrader    gorillawebsocket.Upgrader
+	contextFunc MCPWebSocketContextFunc
+	generator   uuid.UuidGenerator
+	config      MCPWebSocketServerConfig
+	sessions    sync.Map
+	running     bool
+}
+
+func NewMCPWebSocketServer(
+	mcpServer *server.MCPServer,
+	config MCPWebSocketServerConfig,
+	opts ...MCPWebSocketServerOption,
+) *MCPWebSocketServer {
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+
+	s := &MCPWebSocketServer{
+		server:    mcpServer,
+		generator: uuid.NewDefaultUuidGenerator(),
+		config:    config,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.Path, s)
+
+	s.httpServer = &http.Server{
+		Addr:    config.Address,
+		Handler: mux,
+	}
+
+	return s
+}
+
+func (s *MCPWebSocketServer) Server() *server.MCPServer {
+	return s.server
+}
+
+func (s *MCPWebSocketServer) Config() MCPWebSocketServerConfig {
+	return s.config
+}
+
+func (s *MCPWebSocketServer) Start(ctx context.Context) error {
+	logger := log.CtxLogger(ctx)
+
+	logger.Info().Msgf("starting MCP WebSocket server on %s", s.config.Address)
+
+	s.running = true
+
+	err := s.httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error().Err(err).Msgf("failed to start MCP WebSocket server")
+
+		s.running = false
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *MCPWebSocketServer) Stop(ctx context.Context) error {
+	logger := log.CtxLogger(ctx)
+
+	logger.Info().Msg("stopping MCP WebSocket server")
+
+	// hijacked connections are not tracked by the HTTP server shutdown
+	s.sessions.Range(func(key, value any) bool {
+		//nolint:forcetypeassert
+		value.(*MCPWebSocketSession).close()
+
+		return true
+	})
+
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to stop MCP WebSocket server")
+	}
+
+	s.running = false
+
+	return err
+}
+
+func (s *MCPWebSocketServer) Running() bool {
+	return s.running
+}
+
+func (s *MCPWebSocketServer) Info() map[string]any {
+	return map[string]any{
+		"config": map[string]any{
+			"address":          s.config.Address,
+			"path":             s.config.Path,
+			"ping_interval":    s.config.PingInterval.Seconds(),
+			"max_message_size": s.config.MaxMessageSize,
+			"workers":          s.config.Workers,
+		},
+		"status": map[string]any{
+			"running": s.running,
+		},
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection, and serves an MCP session on it until it is closed.
+func (s *MCPWebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already replied with an HTTP error
+		return
+	}
+
+	defer conn.Close()
+
+	session := NewMCPWebSocketSession(s.generator.Generate())
+
+	ctx, cancel := context.WithCancel(s.server.WithContext(r.Context(), session))
+	defer cancel()
+
+	err = s.server.RegisterSession(ctx, session)
+	if err != nil {
+		log.CtxLogger(ctx).Error().Err(err).Msg("failed to register MCP WebSocket session")
+
+		_ = conn.WriteControl(
+			gorillawebsocket.CloseMessage,
+			gorillawebsocket.FormatCloseMessage(gorillawebsocket.CloseInternalServerErr, "session registration failure"),
+			time.Now().Add(DefaultWriteTimeout),
+		)
+
+		return
+	}
+
+	s.sessions.Store(session.SessionID(), session)
+
+	defer func() {
+		s.sessions.Delete(session.SessionID())
+		s.server.UnregisterSession(ctx, session.SessionID())
+	}()
+
+	go s.write(conn, session)
+
+	messages := make(chan []byte)
+
+	var workers sync.WaitGroup
+
+	for range s.config.Workers {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for message := range messages {
+				s.handle(ctx, r, session, message)
+			}
+		}()
+	}
+
+	s.read(ctx, conn, session, messages)
+
+	close(messages)
+	workers.Wait()
+}
+
+// read dispatches the incoming messages to the workers until the connection is closed.
+func (s *MCPWebSocketServer) read(
+	ctx context.Context,
+	conn *gorillawebsocket.Conn,
+	session *MCPWebSocketSession,
+	messages chan<- []byte,
+) {
+	defer session.close()
+
+	if s.config.MaxMessageSize > 0 {
+		conn.SetReadLimit(s.config.MaxMessageSize)
+	}
+
+	if s.config.PingInterval > 0 {
+		// the connection is considered dead if no pong was received for two ping intervals
+		timeout := 2 * s.config.PingInterval
+
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(timeout))
+		})
+	}
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			if gorillawebsocket.IsUnexpectedCloseError(
+				err,
+				gorillawebsocket.CloseNormalClosure,
+				gorillawebsocket.CloseGoingAway,
+				gorillawebsocket.CloseNoStatusReceived,
+			) {
+				log.CtxLogger(ctx).Warn().Err(err).Msg("MCP WebSocket connection closed unexpectedly")
+			}
+
+			return
+		}
+
+		if messageType != gorillawebsocket.TextMessage {
+			continue
+		}
+
+		select {
+		case messages <- message:
+		case <-session.done:
+			return
+		}
+	}
+}
+
+// handle processes an incoming message, and queues its response if any.
+func (s *MCPWebSocketServer) handle(ctx context.Context, r *http.Request, session *MCPWebSocketSession, message []byte) {
+	if s.contextFunc != nil {
+		ctx = s.contextFunc(ctx, r, message)
+	}
+
+	response := s.server.HandleMessage(ctx, message)
+	if response == nil {
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.CtxLogger(ctx).Error().Err(err).Msg("failed to marshal MCP WebSocket response")
+
+		return
+	}
+
+	session.send(data)
+}
+
+// write sends the queued responses and notifications, and the keep alive pings, until the session is closed.
+func (s *MCPWebSocketServer) write(conn *gorillawebsocket.Conn, session *MCPWebSocketSession) {
+	defer conn.Close()
+
+	var pings <-chan time.Time
+	if s.config.PingInterval > 0 {
+		ticker := time.NewTicker(s.config.PingInterval)
+		defer ticker.Stop()
+
+		pings = ticker.C
+	}
+
+	for {
+		var err error
+
+		select {
+		case message := <-session.messages:
+			err = s.writeMessage(conn, message)
+		case notification := <-session.notifications:
+			var data []byte
+			data, err = json.Marshal(notification)
+			if err == nil {
+				err = s.writeMessage(conn, data)
+			}
+		case <-pings:
+			err = conn.WriteControl(gorillawebsocket.PingMessage, nil, time.Now().Add(DefaultWriteTimeout))
+		case <-session.done:
+			_ = conn.WriteControl(
+				gorillawebsocket.CloseMessage,
+				gorillawebsocket.FormatCloseMessage(gorillawebsocket.CloseNormalClosure, ""),
+				time.Now().Add(DefaultWriteTimeout),
+			)
+
+			return
+		}
+
+		if err != nil {
+			session.close()
+
+			return
+		}
+	}
+}
+
+func (s *MCPWebSocketServer) writeMessage(conn *gorillawebsocket.Conn, message []byte) error {
+	err := conn.SetWriteDeadline(time.Now().Add(DefaultWriteTimeout))
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(gorillawebsocket.TextMessage, message)
+}
@@ -0,0 +1,237 @@
+package websocket_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testContextKey struct{}
+
+// newTestClient starts a given MCP WebSocket server in process, and returns a connected and initialized client.
+func newTestClient(t *testing.T, wsServer *websocket.MCPWebSocketServer) *gorillawebsocket.Conn {
+	t.Helper()
+
+	testServer := httptest.NewServer(wsServer)
+	t.Cleanup(testServer.Close)
+
+	conn, resp, err := gorillawebsocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http"), nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	send(t, conn, 0, string(mcp.MethodInitialize), map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"clientInfo":      map[string]any{"name": "test-client", "version": "1.0.0"},
+	})
+
+	response := receive(t, conn)
+	require.Contains(t, response, "result", "unexpected response: %v", response)
+
+	return conn
+}
+
+func send(t *testing.T, conn *gorillawebsocket.Conn, id int, method string, params map[string]any) {
+	t.Helper()
+
+	err := conn.WriteJSON(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(t, err)
+}
+
+func receive(t *testing.T, conn *gorillawebsocket.Conn) map[string]any {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var response map[string]any
+	require.NoError(t, conn.ReadJSON(&response))
+
+	return response
+}
+
+func resultText(t *testing.T, response map[string]any) string {
+	t.Helper()
+
+	require.Contains(t, response, "result", "unexpected response: %v", response)
+
+	content := response["result"].(map[string]any)["content"].([]any)
+	require.NotEmpty(t, content)
+
+	return content[0].(map[string]any)["text"].(string)
+}
+
+func TestMCPWebSocketServerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+	mcpServer.AddTool(
+		mcp.NewTool("whoami"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			session := server.ClientSessionFromContext(ctx)
+
+			return mcp.NewToolResultText(fmt.Sprintf("%s %s %v", session.SessionID(), session.(server.SessionWithClientInfo).GetClientInfo().Name, ctx.Value(testContextKey{}))), nil
+		},
+	)
+
+	var contextCalls atomic.Int64
+
+	wsServer := websocket.NewMCPWebSocketServer(
+		mcpServer,
+		websocket.MCPWebSocketServerConfig{Path: "/ws"},
+		websocket.WithSessionIDGenerator(mcptest.NewSequenceUuidGenerator("ws-session")),
+		websocket.WithWebSocketContextFunc(func(ctx context.Context, r *http.Request, message []byte) context.Context {
+			contextCalls.Add(1)
+
+			return context.WithValue(ctx, testContextKey{}, "enriched")
+		}),
+	)
+
+	conn := newTestClient(t, wsServer)
+
+	send(t, conn, 1, string(mcp.MethodToolsCall), map[string]any{"name": "whoami"})
+
+	assert.Equal(t, "ws-session-1 test-client enriched", resultText(t, receive(t, conn)))
+	assert.Equal(t, int64(2), contextCalls.Load())
+	assert.Equal(t, websocket.DefaultWorkers, wsServer.Config().Workers)
+}
+
+func TestMCPWebSocketServerBoundedWorkers(t *testing.T) {
+	t.Parallel()
+
+	const workers = 2
+
+	var running, maxRunning atomic.Int64
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+	mcpServer.AddTool(
+		mcp.NewTool("block"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			started <- struct{}{}
+			<-release
+
+			return mcp.NewToolResultText("done"), nil
+		},
+	)
+
+	wsServer := websocket.NewMCPWebSocketServer(
+		mcpServer,
+		websocket.MCPWebSocketServerConfig{Path: "/ws", Workers: workers},
+		websocket.WithSessionIDGenerator(mcptest.NewSequenceUuidGenerator("ws-session")),
+	)
+
+	conn := newTestClient(t, wsServer)
+
+	for i := 1; i <= 4; i++ {
+		send(t, conn, i, string(mcp.MethodToolsCall), map[string]any{"name": "block"})
+	}
+
+	for range workers {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("tool calls not started")
+		}
+	}
+
+	// all workers are busy: the remaining messages wait
+	select {
+	case <-started:
+		t.Fatal("more tool calls running than workers")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	ids := map[float64]bool{}
+	for range 4 {
+		response := receive(t, conn)
+		assert.Equal(t, "done", resultText(t, response))
+
+		ids[response["id"].(float64)] = true
+	}
+
+	assert.Len(t, ids, 4)
+	assert.Equal(t, int64(workers), maxRunning.Load())
+}
+
+func TestMCPWebSocketServerStopClosesSessions(t *testing.T) {
+	t.Parallel()
+
+	wsServer := websocket.NewMCPWebSocketServer(server.NewMCPServer("test", "1.0.0"), websocket.MCPWebSocketServerConfig{Path: "/ws"})
+
+	conn := newTestClient(t, wsServer)
+
+	require.NoError(t, wsServer.Stop(context.Background()))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, gorillawebsocket.IsCloseError(err, gorillawebsocket.CloseNormalClosure), "unexpected error: %v", err)
+}
+
+func TestDefaultMCPWebSocketServerFactory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(`
+modules:
+  mcp:
+    server:
+      transport:
+        websocket:
+          path: "/custom"
+          workers: 3
+`), 0o600))
+
+	cfg, err := config.NewDefaultConfigFactory().Create(config.WithFilePaths(dir))
+	require.NoError(t, err)
+
+	wsServer := websocket.NewDefaultMCPWebSocketServerFactory(cfg).Create(server.NewMCPServer("test", "1.0.0"))
+
+	assert.Equal(t, websocket.DefaultAddr, wsServer.Config().Address)
+	assert.Equal(t, "/custom", wsServer.Config().Path)
+	assert.Equal(t, websocket.DefaultPingInterval, wsServer.Config().PingInterval)
+	assert.Equal(t, 3, wsServer.Config().Workers)
+
+	info, err := json.Marshal(wsServer.Info())
+	require.NoError(t, err)
+	assert.Contains(t, string(info), `"workers":3`)
+}
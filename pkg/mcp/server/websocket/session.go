@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	Transport                = "websocket"
+	DefaultNotificationsSize = 100
+)
+
+var (
+	_ server.SessionWithLogging    = (*MCPWebSocketSession)(nil)
+	_ server.SessionWithClientInfo = (*MCPWebSocketSession)(nil)
+)
+
+// MCPWebSocketSession is the MCP client session of a WebSocket connection.
+type MCPWebSocketSession struct {
+	id                 string
+	notifications      chan mcp.JSONRPCNotification
+	messages           chan []byte
+	done               chan struct{}
+	closeOnce          sync.Once
+	initialized        atomic.Bool
+	loggingLevel       atomic.Value
+	clientInfo         atomic.Value
+	clientCapabilities atomic.Value
+}
+
+// NewMCPWebSocketSession returns a new MCPWebSocketSession.
+func NewMCPWebSocketSession(id string) *MCPWebSocketSession {
+	return &MCPWebSocketSession{
+		id:            id,
+		notifications: make(chan mcp.JSONRPCNotification, DefaultNotificationsSize),
+		messages:      make(chan []byte, DefaultNotificationsSize),
+		done:          make(chan struct{}),
+	}
+}
+
+func (s *MCPWebSocketSession) SessionID() string {
+	return s.id
+}
+
+func (s *MCPWebSocketSession) Transport() string {
+	return Transport
+}
+
+func (s *MCPWebSocketSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *MCPWebSocketSession) Initialize() {
+	s.loggingLevel.Store(mcp.LoggingLevelError)
+	s.initialized.Store(true)
+}
+
+func (s *MCPWebSocketSession) Initialized() bool {
+	return s.initialized.Load()
+}
+
+func (s *MCPWebSocketSession) SetLogLevel(level mcp.LoggingLevel) {
+	s.loggingLevel.Store(level)
+}
+
+func (s *MCPWebSocketSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.loggingLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+
+	return mcp.LoggingLevelError
+}
+
+func (s *MCPWebSocketSession) GetClientInfo() mcp.Implementation {
+	if clientInfo, ok := s.clientInfo.Load().(mcp.Implementation); ok {
+		return clientInfo
+	}
+
+	return mcp.Implementation{}
+}
+
+func (s *MCPWebSocketSession) SetClientInfo(clientInfo mcp.Implementation) {
+	s.clientInfo.Store(clientInfo)
+}
+
+func (s *MCPWebSocketSession) GetClientCapabilities() mcp.ClientCapabilities {
+	if clientCapabilities, ok := s.clientCapabilities.Load().(mcp.ClientCapabilities); ok {
+		return clientCapabilities
+	}
+
+	return mcp.ClientCapabilities{}
+}
+
+func (s *MCPWebSocketSession) SetClientCapabilities(clientCapabilities mcp.ClientCapabilities) {
+	s.clientCapabilities.Store(clientCapabilities)
+}
+
+// send queues a message to be written on the connection, unless the session is closed.
+func (s *MCPWebSocketSession) send(message []byte) bool {
+	select {
+	case s.messages <- message:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *MCPWebSocketSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
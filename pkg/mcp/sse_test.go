@@ -0,0 +1,135 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/internal/mcptest"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/auth"
+	"github.com/ekkinox/yokai-mcp/pkg/mcp/server/sse"
+	"github.com/labstack/echo/v4"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+)
+
+// provideTestSSEServer provides the MCP SSE server for a given config, on a given lifecycle and Echo instance.
+func provideTestSSEServer(
+	tb testing.TB,
+	content string,
+	lifecycle *fxtest.Lifecycle,
+	httpServer *echo.Echo,
+) (*sse.MCPSSEServer, error) {
+	tb.Helper()
+
+	cfg := mcptest.NewConfig(tb, content)
+
+	logger, err := log.NewDefaultLoggerFactory().Create()
+	require.NoError(tb, err)
+
+	authenticator, err := auth.NewDefaultMCPAuthenticator(cfg)
+	require.NoError(tb, err)
+
+	return mcp.ProvideMCPSSEServer(mcp.ProvideMCPSSEServerParam{
+		LifeCycle:           lifecycle,
+		Context:             context.Background(),
+		Logger:              logger,
+		Config:              cfg,
+		MCPServer:           server.NewMCPServer("test", "1.0.0"),
+		MCPSSEServerFactory: sse.NewDefaultMCPSSEServerFactory(cfg),
+		MCPSSEServerContextHandler: sse.NewDefaultMCPSSEServerContextHandler(
+			cfg,
+			uuid.NewDefaultUuidGenerator(),
+			noop.NewTracerProvider(),
+			logger,
+		),
+		Authenticator: authenticator,
+		HttpServer:    httpServer,
+	})
+}
+
+const mountedSSEConfig = `
+modules:
+  mcp:
+    server:
+      transport:
+        sse:
+          expose: true
+          mount: true
+`
+
+func TestProvideMCPSSEServerMountedStopClosesSessions(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := fxtest.NewLifecycle(t)
+	httpServer := echo.New()
+
+	sseServer, err := provideTestSSEServer(t, mountedSSEConfig, lifecycle, httpServer)
+	require.NoError(t, err)
+	assert.True(t, sseServer.Mounted())
+
+	testServer := httptest.NewServer(httpServer)
+	defer testServer.Close()
+
+	lifecycle.RequireStart()
+
+	resp, err := http.Get(testServer.URL + sse.DefaultSSEEndpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "event: endpoint"))
+
+	lifecycle.RequireStop()
+
+	// the SSE stream is closed by the server
+	closed := make(chan struct{})
+
+	go func() {
+		defer close(closed)
+
+		for {
+			if _, err := reader.ReadString('\n'); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SSE session not closed on stop")
+	}
+}
+
+func TestProvideMCPSSEServerMountedWithoutHTTPServer(t *testing.T) {
+	t.Parallel()
+
+	_, err := provideTestSSEServer(t, mountedSSEConfig, fxtest.NewLifecycle(t), nil)
+	assert.EqualError(t, err, "cannot mount MCP SSE server: no HTTP server available")
+}
+
+func TestProvideMCPSSEServerMountedWithTLS(t *testing.T) {
+	t.Parallel()
+
+	_, err := provideTestSSEServer(t, mountedSSEConfig+`
+          tls:
+            enabled: true
+`, fxtest.NewLifecycle(t), echo.New())
+	assert.EqualError(t, err, "cannot mount MCP SSE server with TLS: TLS must be configured on the HTTP server")
+}